@@ -21,6 +21,7 @@ package main
 import (
 	"bytes"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
@@ -32,6 +33,7 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -166,6 +168,11 @@ var (
 				log.Fatalf("error parsing input file: %v", err)
 			}
 
+			// Check all the annotations before writing anything.
+			if err := validate(&cfg); err != nil {
+				log.Fatalf("invalid input file %s: %v", input, err)
+			}
+
 			// Find all the known resource types
 			m := make(map[string]bool)
 			for _, a := range cfg.Annotations {
@@ -179,16 +186,6 @@ var (
 			}
 			sort.Strings(knownTypes)
 
-			// Generate variable names if not provided in the yaml.
-			for i := range cfg.Annotations {
-				if cfg.Annotations[i].Name == "" {
-					log.Fatalf("annotation %d in input file missing name", i)
-				}
-				if cfg.Annotations[i].VariableName == "" {
-					cfg.Annotations[i].VariableName = generateVariableName(cfg.Annotations[i].Name)
-				}
-			}
-
 			// sort by name
 			sort.Slice(cfg.Annotations, func(i, j int) bool {
 				return strings.Compare(cfg.Annotations[i].Name, cfg.Annotations[j].Name) < 0
@@ -288,16 +285,73 @@ type Instance struct {
 	Resources []string `json:"resources"`
 }
 
+// validate checks the annotations of cfg, and generates the variable names which are not provided
+// in the yaml. It returns an error listing all the problems found, by annotation index and name:
+// names must be unique Kubernetes qualified names, variable names must be unique, and resources and
+// descriptions must not be empty.
+func validate(cfg *Configuration) error {
+	var errs []string
+	names := map[string]int{}
+	variableNames := map[string]int{}
+	for i := range cfg.Annotations {
+		a := &cfg.Annotations[i]
+		report := func(format string, args ...interface{}) {
+			errs = append(errs, fmt.Sprintf("annotation %d %q: %s", i, a.Name, fmt.Sprintf(format, args...)))
+		}
+
+		validName := false
+		if a.Name == "" {
+			report("missing name")
+		} else if msgs := validation.IsQualifiedName(a.Name); len(msgs) > 0 {
+			report("invalid name: %s", strings.Join(msgs, ", "))
+		} else if j, ok := names[a.Name]; ok {
+			report("duplicate name of annotation %d", j)
+		} else {
+			names[a.Name] = i
+			validName = true
+		}
+
+		if a.VariableName == "" && validName {
+			a.VariableName = generateVariableName(a.Name)
+		}
+		if a.VariableName != "" {
+			if j, ok := variableNames[a.VariableName]; ok {
+				report("duplicate variable name %s of annotation %d", a.VariableName, j)
+			} else {
+				variableNames[a.VariableName] = i
+			}
+		}
+
+		if strings.TrimSpace(a.Description) == "" {
+			report("missing description")
+		}
+		if len(a.Resources) == 0 {
+			report("missing resources")
+		}
+		for _, r := range a.Resources {
+			if r == "" {
+				report("empty resource type")
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d errors:\n%s", len(errs), strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
 func getPackage() string {
 	path, _ := filepath.Abs(output)
 	return filepath.Base(filepath.Dir(path))
 }
 
 func generateVariableName(annoName string) string {
-	// Split the annotation name to separate the namespace/name portions.
-	parts := strings.Split(annoName, "/")
-	ns := parts[0]
-	name := parts[1]
+	// Split the annotation name to separate the namespace/name portions. The namespace is optional.
+	ns, name := "", annoName
+	if i := strings.LastIndex(annoName, "/"); i >= 0 {
+		ns, name = annoName[:i], annoName[i+1:]
+	}
 
 	// First, process the namespace portion ...
 
@@ -0,0 +1,101 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func annotation(name, variableName string, resources ...string) AnnotationVariable {
+	return AnnotationVariable{
+		Instance:     Instance{Name: name, Description: "Some description.", Resources: resources},
+		VariableName: variableName,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Configuration{Annotations: []AnnotationVariable{
+		annotation("sidecar.istio.io/inject", "", "Pod"),
+		annotation("policy.istio.io/check", "PolicyCheck", "Pod"),
+		annotation("readiness.status.sidecar.istio.io/applicationPorts", "", "Pod"),
+	}}
+	if err := validate(&cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var variableNames []string
+	for _, a := range cfg.Annotations {
+		variableNames = append(variableNames, a.VariableName)
+	}
+	expected := []string{"SidecarInject", "PolicyCheck", "SidecarStatusReadinessApplicationPorts"}
+	if !reflect.DeepEqual(variableNames, expected) {
+		t.Errorf("variable names don't match\nReceived: %v\nExpected: %v", variableNames, expected)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	noDescription := annotation("sidecar.istio.io/status", "", "Pod")
+	noDescription.Description = " "
+	cfg := Configuration{Annotations: []AnnotationVariable{
+		annotation("sidecar.istio.io/inject", "", "Pod"),
+		annotation("", "", "Pod"),
+		annotation("sidecar.istio.io/inject", "", "Pod"),
+		annotation("a/b/c", "", "Pod"),
+		annotation("sidecar.istio.io/inject-", "", "Pod"),
+		annotation("policy.istio.io/check", "SidecarInject", "Pod"),
+		annotation("sidecar.istio.io/proxyImage", ""),
+		noDescription,
+	}}
+	err := validate(&cfg)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	expected := []string{
+		"7 errors:",
+		`annotation 1 "": missing name`,
+		`annotation 2 "sidecar.istio.io/inject": duplicate name of annotation 0`,
+		`annotation 3 "a/b/c": invalid name: `,
+		`annotation 4 "sidecar.istio.io/inject-": invalid name: `,
+		`annotation 5 "policy.istio.io/check": duplicate variable name SidecarInject of annotation 0`,
+		`annotation 6 "sidecar.istio.io/proxyImage": missing resources`,
+		`annotation 7 "sidecar.istio.io/status": missing description`,
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("errors don't match\nReceived: %q\nExpected: %q", lines, expected)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, expected[i]) {
+			t.Errorf("error %d doesn't match\nReceived: %q\nExpected: %q", i, line, expected[i])
+		}
+	}
+}
+
+func TestGenerateVariableName(t *testing.T) {
+	cases := map[string]string{
+		"sidecar.istio.io/inject":          "SidecarInject",
+		"networking.istio.io/exportTo":     "NetworkingExportTo",
+		"status.sidecar.istio.io/port":     "SidecarStatusPort",
+		"kubernetes.io/ingress.class":      "IoKubernetesIngressClass",
+		"inject":                           "Inject",
+		"sidecar.istio.io/proxy-cpu_limit": "SidecarProxyCpuLimit",
+	}
+	for name, expected := range cases {
+		if got := generateVariableName(name); got != expected {
+			t.Errorf("generateVariableName(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
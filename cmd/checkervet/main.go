@@ -0,0 +1,159 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// checkervet runs the testlinter and envvarlinter rules as go/analysis analyzers, either
+// standalone or as a go vet tool:
+//
+//	go vet -vettool=$(which checkervet) ./...
+//
+// Built with -buildmode=plugin, it can also be loaded by golangci-lint through AnalyzerPlugin.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	envrules "istio.io/tools/cmd/envvarlinter/rules"
+	testrules "istio.io/tools/cmd/testlinter/rules"
+	"istio.io/tools/pkg/checker"
+	"istio.io/tools/pkg/checker/analyzer"
+)
+
+// configFlag is the flag of the config file, shared with the standalone linters.
+const configFlag = "config"
+
+// AnalyzerPlugin is the symbol golangci-lint looks up in analyzer plugins.
+var AnalyzerPlugin analyzerPlugin
+
+type analyzerPlugin struct{}
+
+// GetAnalyzers returns the analyzers of the plugin, for the rules with their default settings.
+func (analyzerPlugin) GetAnalyzers() []*analysis.Analyzer {
+	all, err := analyzers("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	return all
+}
+
+// analyzers returns an analyzer for each rule of the registries of the linters, including the
+// ones the linters do not enable by default, and for each banned API of the config file at
+// configPath, if not empty. The rules apply to the same files as in the linters, and the banned
+// APIs to all files.
+func analyzers(configPath string) ([]*analysis.Analyzer, error) {
+	config := &checker.Config{}
+	if configPath != "" {
+		var err error
+		if config, err = checker.LoadConfig(configPath); err != nil {
+			return nil, err
+		}
+		if err := configure(configPath, config); err != nil {
+			return nil, err
+		}
+	}
+
+	var all []*analysis.Analyzer
+	seen := map[string]bool{}
+	add := func(rule checker.Rule, factory checker.RulesFactory, doc string) error {
+		if seen[rule.GetID()] {
+			return fmt.Errorf("rule %s is defined twice", rule.GetID())
+		}
+		seen[rule.GetID()] = true
+		all = append(all, analyzer.New(rule, factory, doc))
+		return nil
+	}
+	testRules := testrules.AllRules()
+	for _, info := range testrules.Registry.Rules() {
+		if err := add(info.New(), testRules, info.Description); err != nil {
+			return nil, err
+		}
+	}
+	envRules := envrules.AllRules()
+	for _, info := range envrules.Registry.Rules() {
+		if err := add(info.New(), envRules, info.Description); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range config.BannedAPIRules() {
+		if err := add(rule, goFiles{rule}, ""); err != nil {
+			return nil, fmt.Errorf("invalid banned API in %s: %v", configPath, err)
+		}
+	}
+	return all, nil
+}
+
+// configure applies the settings of the linters in the config file at path to their registries.
+func configure(path string, config *checker.Config) error {
+	var testSettings testrules.Config
+	if err := config.Decode(&testSettings); err != nil {
+		return err
+	}
+	if err := testSettings.Apply(); err != nil {
+		return fmt.Errorf("invalid settings in %s: %v", path, err)
+	}
+	var envSettings envrules.Config
+	if err := config.Decode(&envSettings); err != nil {
+		return err
+	}
+	if err := envSettings.Apply(); err != nil {
+		return fmt.Errorf("invalid settings in %s: %v", path, err)
+	}
+	return nil
+}
+
+// goFiles applies the given rules to every Go file.
+type goFiles []checker.Rule
+
+func (f goFiles) GetRules(absp string, info os.FileInfo) []checker.Rule {
+	if info.IsDir() || !strings.HasSuffix(absp, ".go") {
+		return nil
+	}
+	return f
+}
+
+// configArg returns the value of the -config flag in args. The analyzers are created from the
+// config file before multichecker parses the flags.
+func configArg(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue
+		}
+		if strings.HasPrefix(name, configFlag+"=") {
+			return strings.TrimPrefix(name, configFlag+"=")
+		}
+		if name == configFlag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+func main() {
+	flag.String(configFlag, "", "Path to a YAML configuration file of the linters, for the settings of the rules and the banned APIs.")
+	all, err := analyzers(configArg(os.Args[1:]))
+	if err != nil {
+		log.Fatal(err)
+	}
+	multichecker.Main(all...)
+}
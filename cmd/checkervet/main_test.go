@@ -0,0 +1,65 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkervet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(config, []byte("banned_apis:\n  - id: no_ioutil\n    package: io/ioutil\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := analyzers(configArg([]string{"-json", "--config", config, "./..."}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, a := range all {
+		names[a.Name] = true
+	}
+	// Optional rules of both linters, and the banned APIs of the config file.
+	for _, name := range []string{"skip_issue", "parallel_loop_var", "no_os_env", "env_var_naming", "no_ioutil"} {
+		if !names[name] {
+			t.Errorf("no analyzer for %s among %v", name, names)
+		}
+	}
+}
+
+func TestConfigArg(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		path string
+	}{
+		{[]string{"./..."}, ""},
+		{[]string{"-config=a.yaml", "./..."}, "a.yaml"},
+		{[]string{"-json", "-config", "b.yaml", "./..."}, "b.yaml"},
+		{[]string{"--", "-config=c.yaml"}, ""},
+	} {
+		if path := configArg(tc.args); path != tc.path {
+			t.Errorf("%v: config %q, expected %q", tc.args, path, tc.path)
+		}
+	}
+}
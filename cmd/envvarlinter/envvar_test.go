@@ -29,8 +29,27 @@ func getAbsPath(path string) string {
 
 func TestNoOSEnvRule(t *testing.T) {
 	rpts, _ := getReport([]string{"testdata/"})
-	expectedRpts := []string{getAbsPath("testdata/envuse.go") +
-		":20:6:os.Getenv is disallowed, please see pkg/env instead (no_os_env)",
+	// The dot imported os.LookupEnv is only found with type information.
+	expectedRpts := []string{getAbsPath("testdata/aliased.go") +
+		":23:6:os.Getenv is disallowed, please see pkg/env instead (no_os_env)",
+		getAbsPath("testdata/envuse.go") +
+			":20:6:os.Getenv is disallowed, please see pkg/env instead (no_os_env)",
+		getAbsPath("testdata/envuse.go") +
+			":21:9:os.LookupEnv is disallowed, please see pkg/env instead (no_os_env)"}
+
+	if !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+}
+
+func TestNoOSEnvRuleTyped(t *testing.T) {
+	rpts, _ := getReport([]string{"-types", "testdata/"})
+	expectedRpts := []string{getAbsPath("testdata/aliased.go") +
+		":23:6:os.Getenv is disallowed, please see pkg/env instead (no_os_env)",
+		getAbsPath("testdata/aliased.go") +
+			":24:9:os.LookupEnv is disallowed, please see pkg/env instead (no_os_env)",
+		getAbsPath("testdata/envuse.go") +
+			":20:6:os.Getenv is disallowed, please see pkg/env instead (no_os_env)",
 		getAbsPath("testdata/envuse.go") +
 			":21:9:os.LookupEnv is disallowed, please see pkg/env instead (no_os_env)"}
 
@@ -15,37 +15,87 @@
 package main
 
 import (
-	"flag"
 	"fmt"
 	"os"
 
+	"istio.io/tools/cmd/envvarlinter/rules"
 	"istio.io/tools/pkg/checker"
 )
 
+// formatMarkdown is the output format of the inventory command for the docs site.
+const formatMarkdown = "markdown"
+
 func main() {
-	flag.Parse()
-	exitCode := 0
+	checker.Main(linter())
+}
+
+// linter returns envvarlinter, with the rules of rules.LintRulesList and the built in Whitelist.
+func linter() checker.Linter {
+	return checker.Linter{
+		Name:        "envvarlinter",
+		Rules:       &rules.RulesMatcher{},
+		Whitelist:   Whitelist,
+		Selectors:   "rule_id",
+		Configure:   configure,
+		SelectRules: rules.SelectRules,
+		WriteRules:  rules.WriteRules,
+		Fixes:       true,
+		Commands: []checker.Command{{
+			Name:    "inventory",
+			Formats: "markdown or json",
+			Run:     writeInventory,
+		}},
+	}
+}
+
+// configure applies the envvarlinter settings and the rules section of the config file at path.
+func configure(path string, config *checker.Config) error {
+	var settings rules.Config
+	if err := config.Decode(&settings); err != nil {
+		return err
+	}
+	if err := settings.Apply(); err != nil {
+		return fmt.Errorf("invalid settings in %s: %v", path, err)
+	}
+	if err := rules.ConfigureRules(config.Rules); err != nil {
+		return fmt.Errorf("invalid rules in %s: %v", path, err)
+	}
+	return nil
+}
 
-	items, err := getReport(flag.Args())
+// writeInventory writes the environment variables registered in the given paths to stdout, as a
+// Markdown table or as JSON with -format=json. The variables registered more than once with
+// different types or defaults are reported to stderr, and true is returned if there are any.
+func writeInventory(d *checker.Driver, paths []string) (bool, error) {
+	format := d.Format()
+	if format != checker.FormatText && format != formatMarkdown && format != checker.FormatJSON {
+		return false, fmt.Errorf("inventory writes markdown or json, not %s", format)
+	}
+	inventory := rules.NewInventory()
+	err := checker.CheckWithOptions(paths, inventory, checker.NewWhitelist(nil), checker.NewLintReport(), d.Options())
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		exitCode = 2
+		return false, err
+	}
+	if format == checker.FormatJSON {
+		err = inventory.WriteJSON(os.Stdout)
 	} else {
-		for _, r := range items {
-			fmt.Fprintln(os.Stderr, r)
-			exitCode = 2
-		}
+		err = inventory.WriteMarkdown(os.Stdout)
+	}
+	if err != nil {
+		return false, err
 	}
 
-	os.Exit(exitCode)
+	conflicts := checker.NewLintReport()
+	inventory.ReportConflicts(conflicts)
+	if err := checker.WriteReport(os.Stderr, checker.FormatText, "envvarlinter", conflicts); err != nil {
+		return false, err
+	}
+	return len(conflicts.Items()) > 0, nil
 }
 
+// getReport checks the given paths without the cache, and returns the findings as strings.
 func getReport(args []string) ([]string, error) {
-	matcher := RulesMatcher{}
-	whitelist := checker.NewWhitelist(Whitelist)
-	report := checker.NewLintReport()
-
-	err := checker.Check(args, &matcher, whitelist, report)
+	report, err := checker.NewDriver(linter()).Report(args)
 	if err != nil {
 		return []string{}, err
 	}
@@ -0,0 +1,38 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+// Config holds the envvarlinter settings of the config file, next to the sections read by
+// checker.LoadConfig. It is read with checker.Config.Decode.
+type Config struct {
+	// EnvVarNaming sets the names accepted by the env_var_naming rule.
+	EnvVarNaming EnvVarNamingConfig `json:"env_var_naming"`
+}
+
+// EnvVarNamingConfig sets the prefixes of the names accepted by the env_var_naming rule, instead of
+// DefaultEnvVarPrefixes.
+type EnvVarNamingConfig struct {
+	// Prefixes are the prefixes names should start with, an empty list accepts any prefix.
+	Prefixes *[]string `json:"prefixes"`
+}
+
+// Apply configures the rules of LintRulesList, and the ones created by Registry, with the settings.
+func (c *Config) Apply() error {
+	if c.EnvVarNaming.Prefixes != nil {
+		// The rule replaces the one in LintRulesList as well.
+		return Registry.SetRule(NewEnvVarNamingPrefixes(*c.EnvVarNaming.Prefixes), LintRulesList)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// DefaultEnvVarPrefixes are the prefixes the names of environment variables should start with.
+var DefaultEnvVarPrefixes = []string{"ISTIO_", "PILOT_"}
+
+// upperSnakeCase matches names such as PILOT_ENABLE_FOO.
+var upperSnakeCase = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+// EnvVarNaming flags the names of environment variables registered with pkg/env which are not in
+// upper snake case or do not start with one of its prefixes. Only string literal names are checked.
+type EnvVarNaming struct {
+	prefixes []string
+}
+
+// NewEnvVarNaming creates and returns an EnvVarNaming object requiring DefaultEnvVarPrefixes.
+func NewEnvVarNaming() *EnvVarNaming {
+	return NewEnvVarNamingPrefixes(DefaultEnvVarPrefixes)
+}
+
+// NewEnvVarNamingPrefixes creates and returns an EnvVarNaming object requiring one of prefixes,
+// or none if prefixes is empty.
+func NewEnvVarNamingPrefixes(prefixes []string) *EnvVarNaming {
+	return &EnvVarNaming{prefixes: prefixes}
+}
+
+// GetID returns env_var_naming.
+func (lr *EnvVarNaming) GetID() string {
+	return GetCallerFileName()
+}
+
+// GetSeverity returns warning.
+func (lr *EnvVarNaming) GetSeverity() checker.Severity {
+	return checker.SeverityWarning
+}
+
+// GetConfig returns the accepted prefixes.
+func (lr *EnvVarNaming) GetConfig() string {
+	return strings.Join(lr.prefixes, ",")
+}
+
+// Check verifies the names of the environment variables registered in aNode if it is a file.
+func (lr *EnvVarNaming) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	file, ok := aNode.(*ast.File)
+	if !ok {
+		return
+	}
+	names := importNames(file, EnvPackages...)
+	if len(names) == 0 {
+		return
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		ce, ok := n.(*ast.CallExpr)
+		if !ok || len(ce.Args) == 0 {
+			return true
+		}
+		if fn, ok := matchImportedCall(ce, names); !ok || !registerFunc.MatchString(fn) {
+			return true
+		}
+		lit, ok := ce.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		if !upperSnakeCase.MatchString(name) {
+			lrp.AddItem(fs.Position(lit.Pos()), lr.GetID(),
+				fmt.Sprintf("environment variable %s should be in upper snake case, such as PILOT_ENABLE_FOO", name))
+		} else if !lr.hasPrefix(name) {
+			lrp.AddItem(fs.Position(lit.Pos()), lr.GetID(),
+				fmt.Sprintf("environment variable %s should start with %s", name, strings.Join(lr.prefixes, " or ")))
+		}
+		return true
+	})
+}
+
+// hasPrefix returns true if name starts with one of the prefixes of the rule, or if it has none.
+func (lr *EnvVarNaming) hasPrefix(name string) bool {
+	if len(lr.prefixes) == 0 {
+		return true
+	}
+	for _, p := range lr.prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// ConflictRuleID is the rule ID of the findings about environment variables registered more than
+// once with different types or defaults.
+const ConflictRuleID = "env_var_conflict"
+
+// registerFunc matches the names of the functions of EnvPackages registering a variable, such as
+// RegisterStringVar, and captures its type.
+var registerFunc = regexp.MustCompile(`^Register(\w+)Var$`)
+
+// Inventory lists the environment variables registered with the functions of EnvPackages in the
+// files it is the RulesFactory of.
+type Inventory struct {
+	mu            sync.Mutex
+	registrations []registration
+}
+
+// EnvVar describes an environment variable of the inventory.
+type EnvVar struct {
+	Name string `json:"name"`
+	// Type is the type of the variable, such as string, bool, int, float or duration.
+	Type string `json:"type"`
+	// Default is the Go expression of the default value, such as "foo" or 10 * time.Second.
+	Default     string `json:"default"`
+	Description string `json:"description"`
+	// Locations are the file:line positions of the registrations of the variable.
+	Locations []string `json:"locations"`
+}
+
+// registration is a call registering an environment variable.
+type registration struct {
+	EnvVar
+	pos token.Position
+}
+
+// NewInventory returns an empty inventory.
+func NewInventory() *Inventory {
+	return &Inventory{}
+}
+
+// GetRules returns the rule adding the registrations of absp to the inventory if it is a Go file
+// which is not a test.
+func (inv *Inventory) GetRules(absp string, info os.FileInfo) []checker.Rule {
+	if !isSourceFile(absp, info) {
+		return []checker.Rule{}
+	}
+	return []checker.Rule{&inventoryRule{inv: inv}}
+}
+
+// Vars returns the environment variables of the inventory sorted by name, as defined by their
+// first registration.
+func (inv *Inventory) Vars() []EnvVar {
+	var vars []EnvVar
+	for _, regs := range inv.byName() {
+		v := regs[0].EnvVar
+		v.Locations = nil
+		for _, r := range regs {
+			v.Locations = append(v.Locations, fmt.Sprintf("%s:%d", r.pos.Filename, r.pos.Line))
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// ReportConflicts adds a finding to report for each registration of a variable with another type
+// or default than its first registration.
+func (inv *Inventory) ReportConflicts(report *checker.Report) {
+	for _, regs := range inv.byName() {
+		first := regs[0]
+		for _, r := range regs[1:] {
+			if r.Type != first.Type || r.Default != first.Default {
+				report.AddItem(r.pos, ConflictRuleID, fmt.Sprintf(
+					"environment variable %s is registered as a %s defaulting to %s, but as a %s defaulting to %s at %s:%d",
+					r.Name, r.Type, r.Default, first.Type, first.Default, first.pos.Filename, first.pos.Line))
+			}
+		}
+	}
+}
+
+// WriteJSON writes the environment variables of the inventory to w as a JSON array.
+func (inv *Inventory) WriteJSON(w io.Writer) error {
+	vars := inv.Vars()
+	if vars == nil {
+		vars = []EnvVar{}
+	}
+	data, err := json.MarshalIndent(vars, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteMarkdown writes the environment variables of the inventory to w as a Markdown table.
+func (inv *Inventory) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Variable | Type | Default | Description |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, v := range inv.Vars() {
+		fmt.Fprintf(&b, "| `%s` | %s | `%s` | %s |\n", markdownCell(v.Name), v.Type, markdownCell(v.Default),
+			markdownCell(v.Description))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes s for a cell of a Markdown table.
+func markdownCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.Replace(s, "|", `\|`, -1)
+}
+
+// byName returns the registrations grouped by variable name, sorted by name and then position.
+func (inv *Inventory) byName() [][]registration {
+	inv.mu.Lock()
+	regs := append([]registration{}, inv.registrations...)
+	inv.mu.Unlock()
+	sort.Slice(regs, func(i, j int) bool {
+		x, y := regs[i], regs[j]
+		if x.Name != y.Name {
+			return x.Name < y.Name
+		}
+		if x.pos.Filename != y.pos.Filename {
+			return x.pos.Filename < y.pos.Filename
+		}
+		return x.pos.Offset < y.pos.Offset
+	})
+	var groups [][]registration
+	for i, r := range regs {
+		if i == 0 || r.Name != regs[i-1].Name {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], r)
+	}
+	return groups
+}
+
+func (inv *Inventory) add(regs []registration) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.registrations = append(inv.registrations, regs...)
+}
+
+// inventoryRule adds the registrations of the files it checks to an inventory. It reports no
+// finding.
+type inventoryRule struct {
+	inv *Inventory
+}
+
+// GetID returns inventory.
+func (lr *inventoryRule) GetID() string {
+	return GetCallerFileName()
+}
+
+// Check adds the environment variables registered in aNode to the inventory if it is a file.
+func (lr *inventoryRule) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	file, ok := aNode.(*ast.File)
+	if !ok {
+		return
+	}
+	names := importNames(file, EnvPackages...)
+	if len(names) == 0 {
+		return
+	}
+	var regs []registration
+	ast.Inspect(file, func(n ast.Node) bool {
+		ce, ok := n.(*ast.CallExpr)
+		if !ok || len(ce.Args) != 3 {
+			return true
+		}
+		fn, ok := matchImportedCall(ce, names)
+		if !ok {
+			return true
+		}
+		if m := registerFunc.FindStringSubmatch(fn); m != nil {
+			regs = append(regs, registration{
+				EnvVar: EnvVar{
+					Name:        stringValue(ce.Args[0]),
+					Type:        strings.ToLower(m[1]),
+					Default:     types.ExprString(ce.Args[1]),
+					Description: stringValue(ce.Args[2]),
+				},
+				pos: fs.Position(ce.Pos()),
+			})
+		}
+		return true
+	})
+	lr.inv.add(regs)
+}
+
+// stringValue returns the value of x if it is a string literal, a concatenation of string
+// literals or a constant of the file defined as one, and the Go expression of x otherwise.
+func stringValue(x ast.Expr) string {
+	switch e := x.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			if s, err := strconv.Unquote(e.Value); err == nil {
+				return s
+			}
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			l, r := stringValue(e.X), stringValue(e.Y)
+			if l != types.ExprString(e.X) && r != types.ExprString(e.Y) {
+				return l + r
+			}
+		}
+	case *ast.ParenExpr:
+		return stringValue(e.X)
+	case *ast.Ident:
+		if e.Obj != nil && e.Obj.Kind == ast.Con {
+			if spec, ok := e.Obj.Decl.(*ast.ValueSpec); ok {
+				for i, name := range spec.Names {
+					if name.Name == e.Name && i < len(spec.Values) {
+						return stringValue(spec.Values[i])
+					}
+				}
+			}
+		}
+	}
+	return types.ExprString(x)
+}
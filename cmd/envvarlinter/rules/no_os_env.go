@@ -15,13 +15,44 @@
 package rules
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"path"
+	"strconv"
 
 	"istio.io/tools/pkg/checker"
 )
 
-// NoOsEnv flags an error if os.Getenv or os.LookupEnv are used.
+// viperPackage is the import path of viper, whose AutomaticEnv reads all the environment variables
+// matching its keys.
+const viperPackage = "github.com/spf13/viper"
+
+// envFuncs are the functions reading the process environment, by import path.
+var envFuncs = map[string][]string{
+	"os":         {"Getenv", "LookupEnv", "Environ", "ExpandEnv"},
+	"syscall":    {"Getenv"},
+	viperPackage: {"AutomaticEnv"},
+}
+
+// getenvFuncs are the functions of envFuncs which os.Expand reads the environment with.
+var getenvFuncs = []string{"os", "syscall"}
+
+// envPackage is the import path of pkg/env, which the suggested fixes read variables with.
+const envPackage = "istio.io/pkg/env"
+
+// envMethods are the methods of an env.StringVar which replace the functions reading a single
+// variable in the suggested fixes.
+var envMethods = map[string]string{
+	"Getenv":    "Get",
+	"LookupEnv": "Lookup",
+}
+
+// NoOsEnv flags an error if the process environment is read other than with pkg/env: with
+// os.Getenv, os.LookupEnv, os.Environ, os.ExpandEnv, os.Expand with os.Getenv, syscall.Getenv or
+// viper.AutomaticEnv. Calls reading a variable named by a string literal, such as os.Getenv("A"),
+// have a suggested fix reading it with env.RegisterStringVar("A", "", "").Get() instead.
 type NoOsEnv struct {
 }
 
@@ -35,13 +66,156 @@ func (lr *NoOsEnv) GetID() string {
 	return GetCallerFileName()
 }
 
-// Check verifies there are no calls to os.Getenv or os.LookupEnv
+// GetSeverity returns error.
+func (lr *NoOsEnv) GetSeverity() checker.Severity {
+	return checker.SeverityError
+}
+
+// Check verifies there are no calls reading the environment in aNode if it is a file, including
+// through renamed imports.
 func (lr *NoOsEnv) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
-	if ce, ok := aNode.(*ast.CallExpr); ok {
-		if MatchCallExpr(ce, "os", "Getenv") {
-			lrp.AddItem(fs.Position(ce.Pos()), lr.GetID(), "os.Getenv is disallowed, please see pkg/env instead")
-		} else if MatchCallExpr(ce, "os", "LookupEnv") {
-			lrp.AddItem(fs.Position(ce.Pos()), lr.GetID(), "os.LookupEnv is disallowed, please see pkg/env instead")
+	file, ok := aNode.(*ast.File)
+	if !ok {
+		return
+	}
+	imports := map[string]map[string]bool{}
+	for pkgPath := range envFuncs {
+		imports[pkgPath] = importNames(file, pkgPath)
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		ce, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		for pkgPath, funcs := range envFuncs {
+			if fn, ok := matchImportedCall(ce, imports[pkgPath]); ok && contains(funcs, fn) {
+				lr.report(file, ce, pkgPath, fn, fs, lrp)
+			}
+		}
+		if fn, ok := matchImportedCall(ce, imports["os"]); ok && fn == "Expand" && len(ce.Args) == 2 {
+			for _, pkgPath := range getenvFuncs {
+				if fn, ok := matchImportedCall(&ast.CallExpr{Fun: ce.Args[1]}, imports[pkgPath]); ok && fn == "Getenv" {
+					lr.report(file, ce, "os", "Expand", fs, lrp)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// TypesOptional returns true, as Check recognises the calls through the names os, syscall and
+// viper are imported as without type information. Types are only loaded for the rule with -types.
+func (lr *NoOsEnv) TypesOptional() bool {
+	return true
+}
+
+// CheckTyped verifies there are no calls reading the environment in aNode if it is a file,
+// including through renamed and dot imports, and calls to the AutomaticEnv method of a
+// viper.Viper.
+func (lr *NoOsEnv) CheckTyped(aNode ast.Node, fs *token.FileSet, info *types.Info, _ *types.Package, lrp *checker.Report) {
+	file, ok := aNode.(*ast.File)
+	if !ok {
+		return
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ce, ok := n.(*ast.CallExpr); ok {
+			lr.checkTypedCall(file, ce, fs, info, lrp)
+		}
+		return true
+	})
+}
+
+// checkTypedCall verifies that the call ce of file does not read the environment.
+func (lr *NoOsEnv) checkTypedCall(file *ast.File, ce *ast.CallExpr, fs *token.FileSet, info *types.Info, lrp *checker.Report) {
+	for pkgPath, funcs := range envFuncs {
+		for _, fn := range funcs {
+			if checker.MatchCallFunc(info, ce, pkgPath, fn) {
+				lr.report(file, ce, pkgPath, fn, fs, lrp)
+			}
+		}
+	}
+	if checker.MatchCallFunc(info, ce, "os", "Expand") && len(ce.Args) == 2 {
+		for _, pkgPath := range getenvFuncs {
+			// The mapping function is not called, but resolves the same way as a callee.
+			if checker.MatchCallFunc(info, &ast.CallExpr{Fun: ce.Args[1]}, pkgPath, "Getenv") {
+				lr.report(file, ce, "os", "Expand", fs, lrp)
+			}
+		}
+	}
+	if sel, ok := ce.Fun.(*ast.SelectorExpr); ok {
+		if s := info.Selections[sel]; s != nil && s.Kind() == types.MethodVal {
+			if fn := s.Obj(); fn.Pkg() != nil && fn.Pkg().Path() == viperPackage && fn.Name() == "AutomaticEnv" {
+				lr.report(file, ce, viperPackage, "AutomaticEnv", fs, lrp)
+			}
+		}
+	}
+}
+
+// report reports the call ce of file to the function fn of pkgPath, with a fix reading the
+// variable with pkg/env if there is one.
+func (lr *NoOsEnv) report(file *ast.File, ce *ast.CallExpr, pkgPath string, fn string, fs *token.FileSet, lrp *checker.Report) {
+	msg := fmt.Sprintf("%s.%s is disallowed, please see pkg/env instead", path.Base(pkgPath), fn)
+	if edits := envFix(file, ce, fn, fs); edits != nil {
+		lrp.AddItemWithFix(fs.Position(ce.Pos()), lr.GetID(), msg, edits...)
+		return
+	}
+	lrp.AddItem(fs.Position(ce.Pos()), lr.GetID(), msg)
+}
+
+// envFix returns the edits replacing the call ce of file to fn, such as os.Getenv("A"), with
+// env.RegisterStringVar("A", "", "").Get(), and importing pkg/env if file does not. It returns
+// nil if fn does not read a single variable named by a string literal, or if env is declared in
+// file as another name.
+func envFix(file *ast.File, ce *ast.CallExpr, fn string, fs *token.FileSet) []checker.TextEdit {
+	method, ok := envMethods[fn]
+	if !ok || len(ce.Args) != 1 {
+		return nil
+	}
+	if lit, ok := ce.Args[0].(*ast.BasicLit); !ok || lit.Kind != token.STRING {
+		return nil
+	}
+	var edits []checker.TextEdit
+	name := ""
+	for n := range importNames(file, envPackage) {
+		name = n
+	}
+	if name == "" {
+		if file.Scope.Lookup("env") != nil {
+			return nil
+		}
+		imp, ok := importEdit(file, fs)
+		if !ok {
+			return nil
+		}
+		name = "env"
+		edits = append(edits, imp)
+	}
+	call := fmt.Sprintf("%s.RegisterStringVar(%s, \"\", \"\").%s()", name, ce.Args[0].(*ast.BasicLit).Value, method)
+	return append(edits, checker.NewTextEdit(fs, ce.Pos(), ce.End(), call))
+}
+
+// importEdit returns the edit adding pkg/env to the first import declaration of file, or false if
+// file has none.
+func importEdit(file *ast.File, fs *token.FileSet) (checker.TextEdit, bool) {
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		if gd.Lparen.IsValid() {
+			return checker.NewTextEdit(fs, gd.Lparen+1, gd.Lparen+1, "\n\t"+strconv.Quote(envPackage)), true
+		}
+		return checker.NewTextEdit(fs, gd.Pos(), gd.Pos(), "import "+strconv.Quote(envPackage)+"\n"), true
+	}
+	return checker.TextEdit{}, false
+}
+
+// contains returns true if names contains name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
 		}
 	}
+	return false
 }
@@ -0,0 +1,85 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"io"
+	"os"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// SourceFile is the kind of files envvarlinter checks: Go files which are not tests.
+const SourceFile = "source"
+
+// LintRulesList is the list of rules applied to non-test Go files.
+var LintRulesList = []checker.Rule{
+	NewNoOsEnv(),
+}
+
+// Registry holds all the rules of envvarlinter, so that they can be listed and selected by name.
+var Registry = checker.NewRegistry([]string{SourceFile},
+	checker.RuleInfo{
+		Description: "Environment variables must be read with pkg/env instead of os, syscall or viper.",
+		Kinds:       []string{SourceFile},
+		New:         func() checker.Rule { return NewNoOsEnv() },
+	},
+	checker.RuleInfo{
+		Description: "Names of environment variables registered with pkg/env must be in upper snake case with a known prefix.",
+		Kinds:       []string{SourceFile},
+		New:         func() checker.Rule { return NewEnvVarNaming() },
+	},
+)
+
+// AllRules returns a RulesFactory which returns all the rules of Registry for the Go files which
+// are not tests, including the rules which are not in LintRulesList.
+func AllRules() checker.RulesFactory {
+	return Registry.AllRules(func(absp string, info os.FileInfo) (string, bool) {
+		return SourceFile, isSourceFile(absp, info)
+	})
+}
+
+// ConfigureRules replaces LintRulesList with the rules listed for source files in policy, the rules
+// section of a config file.
+func ConfigureRules(policy map[string][]string) error {
+	for kind, ids := range policy {
+		selected, err := Registry.NewRules(kind, ids)
+		if err != nil {
+			return err
+		}
+		LintRulesList = selected
+	}
+	return nil
+}
+
+// SelectRules applies the rules selected by the -enable and -disable flags, lists of rule_id or
+// source:rule_id, to LintRulesList.
+func SelectRules(enable, disable string) error {
+	enabled, err := Registry.ParseSelectors(enable)
+	if err != nil {
+		return err
+	}
+	disabled, err := Registry.ParseSelectors(disable)
+	if err != nil {
+		return err
+	}
+	LintRulesList = Registry.Select(SourceFile, LintRulesList, enabled, disabled)
+	return nil
+}
+
+// WriteRules writes the table of all rules, and whether they are enabled, to w.
+func WriteRules(w io.Writer) error {
+	return Registry.WriteRules(w, map[string][]checker.Rule{SourceFile: LintRulesList})
+}
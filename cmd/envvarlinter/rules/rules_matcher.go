@@ -12,13 +12,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package rules
 
 import (
 	"os"
 	"strings"
 
-	"istio.io/tools/cmd/envvarlinter/rules"
 	"istio.io/tools/pkg/checker"
 )
 
@@ -26,14 +25,16 @@ import (
 type RulesMatcher struct {
 }
 
-// GetRules checks path absp and decides whether absp is a test file. It returns true and test type
-// for a test file. If path absp should be skipped, it returns false.
+// GetRules checks path absp and returns LintRulesList if it is a Go file which is not a test.
 func (rf *RulesMatcher) GetRules(absp string, info os.FileInfo) []checker.Rule {
-	// Skip path which is a directory, a go test file, or not a go file at all.
-	paths := strings.Split(absp, "/")
-	if len(paths) == 0 || info.IsDir() || strings.HasSuffix(absp, "_test.go") || !strings.HasSuffix(absp, ".go") {
+	if !isSourceFile(absp, info) {
 		return []checker.Rule{}
 	}
+	return LintRulesList
+}
 
-	return []checker.Rule{rules.NewNoOsEnv()}
+// isSourceFile returns true if path absp is a Go file which is not a test.
+func isSourceFile(absp string, info os.FileInfo) bool {
+	// Skip path which is a directory, a go test file, or not a go file at all.
+	return !info.IsDir() && !strings.HasSuffix(absp, "_test.go") && strings.HasSuffix(absp, ".go")
 }
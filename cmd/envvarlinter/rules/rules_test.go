@@ -0,0 +1,130 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"istio.io/tools/pkg/checker"
+	"istio.io/tools/pkg/checker/checkertest"
+)
+
+func TestNoOsEnv(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewNoOsEnv(), "no_os_env")
+	checkertest.RunWithOptions(t, checkertest.TestData(), NewNoOsEnv(), checker.Options{LoadTypes: true}, "no_os_env")
+}
+
+func TestNoOsEnvTyped(t *testing.T) {
+	checkertest.RunWithOptions(t, checkertest.TestData(), NewNoOsEnv(), checker.Options{LoadTypes: true}, "no_os_env_typed")
+}
+
+func TestNoOsEnvFix(t *testing.T) {
+	report := checkertest.Run(t, checkertest.TestData(), NewNoOsEnv(), "no_os_env_fix")
+
+	var diff bytes.Buffer
+	if err := checker.ApplyFixes(report, &diff); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"+\t\"istio.io/pkg/env\"\n",
+		"+\tif env.RegisterStringVar(\"FIX_A\", \"\", \"\").Get() == \"\" {",
+		"+\treturn env.RegisterStringVar(\"FIX_B\", \"\", \"\").Lookup()",
+	} {
+		if !strings.Contains(diff.String(), line) {
+			t.Errorf("fixes don't contain %q\n%s", line, diff.String())
+		}
+	}
+	if strings.Contains(diff.String(), "RegisterStringVar(name") {
+		t.Errorf("expected no fix for a variable not named by a literal\n%s", diff.String())
+	}
+}
+
+func TestEnvVarNaming(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewEnvVarNaming(), "env_var_naming")
+}
+
+func TestEnvVarNamingPrefixes(t *testing.T) {
+	defer func(rules []checker.Rule) { LintRulesList = rules }(LintRulesList)
+	LintRulesList = []checker.Rule{NewNoOsEnv(), NewEnvVarNaming()}
+
+	prefixes := []string{}
+	c := Config{EnvVarNaming: EnvVarNamingConfig{Prefixes: &prefixes}}
+	if err := c.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = Registry.SetRule(NewEnvVarNaming()) }()
+	checkertest.Run(t, checkertest.TestData(), LintRulesList[1], "env_var_naming_prefixes")
+	if rules, err := Registry.NewRules(SourceFile, []string{"env_var_naming"}); err != nil || !reflect.DeepEqual(rules, LintRulesList[1:]) {
+		t.Errorf("Registry doesn't create the configured rule: %v, %v", rules, err)
+	}
+}
+
+func TestInventory(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join(checkertest.TestData(), "inventory"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inventory := NewInventory()
+	if err := checker.Check([]string{dir}, inventory, checker.NewWhitelist(nil), checker.NewLintReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "inventory.go")
+	other := filepath.Join(dir, "other", "other.go")
+	at := func(file string, line int) string {
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+	expected := []EnvVar{
+		{Name: "PILOT_ENABLE_FOO", Type: "bool", Default: "true", Description: "Whether foo is enabled.",
+			Locations: []string{at(file, 26)}},
+		{Name: "PILOT_TIMEOUT", Type: "duration", Default: "10 * time.Second", Description: "How long to wait for a | response.",
+			Locations: []string{at(file, 28), at(other, 24)}},
+		{Name: "POD_NAME", Type: "string", Default: `""`, Locations: []string{at(file, 32), at(other, 26)}},
+	}
+	if vars := inventory.Vars(); !reflect.DeepEqual(vars, expected) {
+		t.Errorf("inventory doesn't match\nReceived: %+v\nExpected: %+v", vars, expected)
+	}
+
+	report := checker.NewLintReport()
+	inventory.ReportConflicts(report)
+	conflicts := []string{
+		at(other, 24) + ":12:environment variable PILOT_TIMEOUT is registered as a duration defaulting to 5 * time.Second, " +
+			"but as a duration defaulting to 10 * time.Second at " + at(file, 28) + " (" + ConflictRuleID + ")",
+	}
+	if items := report.Items(); !reflect.DeepEqual(items, conflicts) {
+		t.Errorf("conflicts don't match\nReceived: %v\nExpected: %v", items, conflicts)
+	}
+
+	var out bytes.Buffer
+	if err := inventory.WriteMarkdown(&out); err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expectedRows := []string{
+		"| Variable | Type | Default | Description |",
+		"| --- | --- | --- | --- |",
+		"| `PILOT_ENABLE_FOO` | bool | `true` | Whether foo is enabled. |",
+		"| `PILOT_TIMEOUT` | duration | `10 * time.Second` | How long to wait for a \\| response. |",
+		"| `POD_NAME` | string | `\"\"` |  |",
+	}
+	if !reflect.DeepEqual(rows, expectedRows) {
+		t.Errorf("inventory Markdown doesn't match\nReceived: %q\nExpected: %q", rows, expectedRows)
+	}
+}
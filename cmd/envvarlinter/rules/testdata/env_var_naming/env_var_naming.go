@@ -0,0 +1,30 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envvarnaming
+
+import (
+	ienv "istio.io/pkg/env"
+)
+
+const name = "not_checked"
+
+var (
+	_ = ienv.RegisterBoolVar("PILOT_ENABLE_FOO", true, "")
+	_ = ienv.RegisterStringVar("ISTIO_META_NAME", "", "")
+	_ = ienv.RegisterStringVar("POD_NAME", "", "")        // want `environment variable POD_NAME should start with ISTIO_ or PILOT_`
+	_ = ienv.RegisterIntVar("PILOT_maxConns", 1, "")      // want `environment variable PILOT_maxConns should be in upper snake case`
+	_ = ienv.RegisterDurationVar("PILOT__TIMEOUT", 0, "") // want `should be in upper snake case`
+	_ = ienv.RegisterStringVar(name, "", "")
+)
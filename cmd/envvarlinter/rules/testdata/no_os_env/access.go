@@ -0,0 +1,35 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noosenv
+
+import (
+	"os"
+	sys "syscall"
+
+	"github.com/spf13/viper"
+)
+
+func access() {
+	_ = os.Environ()                  // want `os.Environ is disallowed`
+	_ = os.ExpandEnv("$HOME")         // want `os.ExpandEnv is disallowed`
+	_ = os.Expand("$HOME", os.Getenv) // want `os.Expand is disallowed`
+	_ = os.Expand("$HOME", mapping)
+	_, _ = sys.Getenv("C") // want `syscall.Getenv is disallowed`
+	viper.AutomaticEnv()   // want `viper.AutomaticEnv is disallowed`
+}
+
+func mapping(key string) string {
+	return key
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noosenvtyped
+
+import (
+	. "os"
+
+	"github.com/spf13/viper"
+)
+
+func access() {
+	_ = Getenv("A")             // want `os.Getenv is disallowed`
+	_ = Expand("$HOME", Getenv) // want `os.Expand is disallowed`
+	v := viper.New()
+	v.AutomaticEnv() // want `viper.AutomaticEnv is disallowed`
+}
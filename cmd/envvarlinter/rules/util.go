@@ -17,11 +17,16 @@ package rules
 import (
 	"go/ast"
 	"log"
+	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
+// EnvPackages are the import paths of the packages registering environment variables.
+var EnvPackages = []string{"istio.io/pkg/env", "istio.io/istio/pkg/env"}
+
 // GetCallerFileName returns filename of caller without file extension.
 func GetCallerFileName() string {
 	if _, filename, _, ok := runtime.Caller(1); ok {
@@ -45,3 +50,38 @@ func MatchCallExpr(ce *ast.CallExpr, pn string, mn string) bool {
 	}
 	return false
 }
+
+// importNames returns the names file refers to the packages imported from any of pkgPaths by.
+// Dot imports are not included.
+func importNames(file *ast.File, pkgPaths ...string) map[string]bool {
+	names := map[string]bool{}
+	for _, spec := range file.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		for _, pkgPath := range pkgPaths {
+			if p != pkgPath {
+				continue
+			}
+			if spec.Name == nil {
+				names[path.Base(p)] = true
+			} else if spec.Name.Name != "." && spec.Name.Name != "_" {
+				names[spec.Name.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// matchImportedCall returns the name of the function called by ce if it is a function of a
+// package imported by one of names, as returned by importNames.
+func matchImportedCall(ce *ast.CallExpr, names map[string]bool) (string, bool) {
+	if sel, ok := ce.Fun.(*ast.SelectorExpr); ok {
+		// Names declared in the file have an Obj, and shadow the import.
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Obj == nil && names[pkg.Name] {
+			return sel.Sel.Name, true
+		}
+	}
+	return "", false
+}
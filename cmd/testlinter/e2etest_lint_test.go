@@ -24,11 +24,11 @@ import (
 
 func TestE2eTestSkipByIssueRule(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[E2eTest] = []checker.Rule{rules.NewSkipByIssue()}
+	rules.LintRulesList[rules.E2eTest] = []checker.Rule{rules.NewSkipByIssue()}
 
 	rpts, _ := getReport([]string{"testdata/"})
 	expectedRpts := []string{
-		getAbsPath("testdata/e2e/e2e_test.go") + ":26:2:Only t.Skip() is allowed and t.Skip() should contain an url to GitHub issue. (skip_issue)",
+		getAbsPath("testdata/e2e/e2e_test.go") + ":26:2:Only t.Skip() and t.Skipf() are allowed and they should contain an url to an issue. (skip_issue)",
 	}
 
 	if !reflect.DeepEqual(rpts, expectedRpts) {
@@ -38,7 +38,7 @@ func TestE2eTestSkipByIssueRule(t *testing.T) {
 
 func TestE2eTestSkipByShortRule(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[E2eTest] = []checker.Rule{rules.NewSkipByShort()}
+	rules.LintRulesList[rules.E2eTest] = []checker.Rule{rules.NewSkipByShort()}
 
 	rpts, _ := getReport([]string{"testdata/"})
 	expectedRpts := []string{getAbsPath("testdata/e2e/e2e_test.go") +
@@ -24,12 +24,12 @@ import (
 
 func TestIntegTestSkipByIssueRule(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[IntegTest] = []checker.Rule{rules.NewSkipByIssue()}
+	rules.LintRulesList[rules.IntegTest] = []checker.Rule{rules.NewSkipByIssue()}
 
 	rpts, _ := getReport([]string{"testdata/"})
 	expectedRpts := []string{
-		getAbsPath("testdata/integration/integtest_test.go") + ":26:2:Only t.Skip() is allowed and t.Skip() should contain an url to GitHub issue. (skip_issue)",
-		getAbsPath("testdata/integtest_integ_test.go") + ":26:2:Only t.Skip() is allowed and t.Skip() should contain an url to GitHub issue. (skip_issue)"}
+		getAbsPath("testdata/integration/integtest_test.go") + ":26:2:Only t.Skip() and t.Skipf() are allowed and they should contain an url to an issue. (skip_issue)",
+		getAbsPath("testdata/integtest_integ_test.go") + ":26:2:Only t.Skip() and t.Skipf() are allowed and they should contain an url to an issue. (skip_issue)"}
 
 	if !reflect.DeepEqual(rpts, expectedRpts) {
 		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
@@ -38,7 +38,7 @@ func TestIntegTestSkipByIssueRule(t *testing.T) {
 
 func TestIntegTestSkipByShortRule(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[IntegTest] = []checker.Rule{rules.NewSkipByShort()}
+	rules.LintRulesList[rules.IntegTest] = []checker.Rule{rules.NewSkipByShort()}
 
 	rpts, _ := getReport([]string{"testdata/"})
 	expectedRpts := []string{getAbsPath("testdata/integration/integtest_test.go") +
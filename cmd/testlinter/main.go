@@ -15,36 +15,76 @@
 package main
 
 import (
-	"flag"
 	"fmt"
 	"os"
 
+	"istio.io/tools/cmd/testlinter/rules"
 	"istio.io/tools/pkg/checker"
 )
 
+// formatCSV is the output format of the inventory command for spreadsheets.
+const formatCSV = "csv"
+
 func main() {
-	flag.Parse()
-	exitCode := 0
+	checker.Main(linter())
+}
+
+// linter returns testlinter, with the rules of rules.LintRulesList and the built in Whitelist.
+func linter() checker.Linter {
+	return checker.Linter{
+		Name:         "testlinter",
+		Rules:        &rules.RulesMatcher{},
+		Whitelist:    Whitelist,
+		Selectors:    "rule_id or test_type:rule_id where the test type is unit, integration or e2e",
+		Configure:    configure,
+		SelectRules:  rules.SelectRules,
+		WriteRules:   rules.WriteRules,
+		OptionalTags: rules.TestTypeTags(),
+		Fixes:        true,
+		Commands: []checker.Command{{
+			Name:    "inventory",
+			Formats: "json or csv",
+			Run:     writeInventory,
+		}},
+	}
+}
+
+// configure applies the testlinter settings and the rules section of the config file at path.
+func configure(path string, config *checker.Config) error {
+	var settings rules.Config
+	if err := config.Decode(&settings); err != nil {
+		return err
+	}
+	if err := settings.Apply(); err != nil {
+		return fmt.Errorf("invalid settings in %s: %v", path, err)
+	}
+	if err := rules.ConfigureRules(config.Rules); err != nil {
+		return fmt.Errorf("invalid rules in %s: %v", path, err)
+	}
+	return nil
+}
 
-	items, err := getReport(flag.Args())
+// writeInventory writes the inventory of the tests in the given paths to stdout, as JSON or as
+// CSV with -format=csv.
+func writeInventory(d *checker.Driver, paths []string) (bool, error) {
+	format := d.Format()
+	if format != checker.FormatText && format != checker.FormatJSON && format != formatCSV {
+		return false, fmt.Errorf("inventory writes json or csv, not %s", format)
+	}
+	inventory := rules.NewInventory()
+	err := checker.CheckWithOptions(paths, inventory, checker.NewWhitelist(nil), checker.NewLintReport(), d.Options())
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		exitCode = 2
-	} else {
-		for _, r := range items {
-			fmt.Fprintln(os.Stderr, r)
-			exitCode = 2
-		}
-	}
-	os.Exit(exitCode)
+		return false, err
+	}
+	if format == formatCSV {
+		return false, inventory.WriteCSV(os.Stdout)
+	}
+	return false, inventory.WriteJSON(os.Stdout)
 }
 
+// getReport checks the given paths without the cache, and returns the findings as strings.
 func getReport(args []string) ([]string, error) {
-	matcher := RulesMatcher{}
-	whitelist := checker.NewWhitelist(Whitelist)
-	report := checker.NewLintReport()
-
-	err := checker.Check(args, &matcher, whitelist, report)
+	report, err := checker.NewDriver(linter()).Report(args)
 	if err != nil {
 		return []string{}, err
 	}
@@ -0,0 +1,183 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Sources of the test type of a file, in the default order of precedence.
+const (
+	// DirectiveSource is a // +testtype=<type> comment before the package clause.
+	DirectiveSource = "directive"
+	// TagSource is a build constraint requiring one of the tags of tagTestTypes.
+	TagSource = "tag"
+	// PathSource is the path of the file, see GetTestType.
+	PathSource = "path"
+)
+
+// ClassificationPrecedence lists the sources of the test type of a file, from the one which
+// takes precedence. Sources which are not listed are not used. It can be set by the
+// classification section of the config file.
+var ClassificationPrecedence = []string{DirectiveSource, TagSource, PathSource}
+
+// testTypeDirective is the prefix of the comment setting the test type of a file.
+const testTypeDirective = "// +testtype="
+
+// tagTestTypes are the build tags marking test files of a type.
+var tagTestTypes = map[string]TestType{
+	"integ":       IntegTest,
+	"integration": IntegTest,
+	"e2e":         E2eTest,
+}
+
+// TestTypeTags returns the build tags marking test files of a type, sorted.
+func TestTypeTags() []string {
+	tags := make([]string, 0, len(tagTestTypes))
+	for tag := range tagTestTypes {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ValidatePrecedence returns an error if precedence has unknown or duplicate sources.
+func ValidatePrecedence(precedence []string) error {
+	seen := map[string]bool{}
+	for _, source := range precedence {
+		if source != DirectiveSource && source != TagSource && source != PathSource {
+			return fmt.Errorf("unknown test type source %q, expected %s, %s or %s", source, DirectiveSource,
+				TagSource, PathSource)
+		}
+		if seen[source] {
+			return fmt.Errorf("test type source %q is listed twice", source)
+		}
+		seen[source] = true
+	}
+	return nil
+}
+
+// ParseTestType returns the test type with the given name: unit, integration or e2e.
+func ParseTestType(name string) (TestType, bool) {
+	for _, t := range TestTypes {
+		if t.String() == name {
+			return t, true
+		}
+	}
+	return UnitTest, false
+}
+
+// testTypeSources returns the test types given by each source for the test file at absp, whose
+// header holds the comment lines before its package clause. Sources which give no type, such as
+// the path of a file in no e2e or integration directory, are not in the result. A directive with
+// an unknown type is returned as an error.
+func testTypeSources(absp string, header []string) (map[string]TestType, error) {
+	sources := map[string]TestType{}
+	var err error
+	if t, ok := pathTestType(absp); ok {
+		sources[PathSource] = t
+	}
+	for _, line := range header {
+		if strings.HasPrefix(line, testTypeDirective) {
+			name := strings.TrimSpace(strings.TrimPrefix(line, testTypeDirective))
+			if t, ok := ParseTestType(name); ok {
+				sources[DirectiveSource] = t
+			} else {
+				err = fmt.Errorf("unknown test type %q in %q, expected unit, integration or e2e", name, line)
+			}
+		} else if t, ok := constraintTestType(line); ok {
+			if _, found := sources[TagSource]; !found {
+				sources[TagSource] = t
+			}
+		}
+	}
+	return sources, err
+}
+
+// constraintTestType returns the test type of the first tag of tagTestTypes required by line, if
+// it is a // +build or //go:build constraint.
+func constraintTestType(line string) (TestType, bool) {
+	var expr string
+	if strings.HasPrefix(line, "// +build ") {
+		expr = strings.TrimPrefix(line, "// +build ")
+	} else if strings.HasPrefix(line, "//go:build ") {
+		expr = strings.TrimPrefix(line, "//go:build ")
+	} else {
+		return UnitTest, false
+	}
+	tags := strings.FieldsFunc(expr, func(r rune) bool {
+		return strings.ContainsRune(" ,()&|", r)
+	})
+	for _, tag := range tags {
+		if t, ok := tagTestTypes[tag]; ok {
+			return t, true
+		}
+	}
+	return UnitTest, false
+}
+
+// classify returns the test type from the source that takes precedence, or UnitTest if no source
+// gives a type.
+func classify(sources map[string]TestType) TestType {
+	for _, source := range ClassificationPrecedence {
+		if t, ok := sources[source]; ok {
+			return t
+		}
+	}
+	return UnitTest
+}
+
+// readHeader returns the comment lines of the Go file at path before its package clause.
+func readHeader(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var header []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if strings.HasPrefix(line, "//") {
+			header = append(header, line)
+		}
+	}
+	return header, scanner.Err()
+}
+
+// fileHeader returns the comment lines of file before its package clause.
+func fileHeader(file *ast.File) []string {
+	var header []string
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, "//") {
+				header = append(header, strings.TrimSpace(c.Text))
+			}
+		}
+	}
+	return header
+}
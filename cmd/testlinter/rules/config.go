@@ -0,0 +1,84 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"istio.io/tools/pkg/checker"
+)
+
+// Config holds the testlinter settings of the config file, next to the sections read by
+// checker.LoadConfig. It is read with checker.Config.Decode.
+type Config struct {
+	// SkipIssue sets the issue urls accepted by the skip_issue rule.
+	SkipIssue SkipIssueConfig `json:"skip_issue"`
+	// Classification sets how the test type of files is decided.
+	Classification ClassificationConfig `json:"classification"`
+}
+
+// ClassificationConfig sets how the test type of files is decided, see GetTestType.
+type ClassificationConfig struct {
+	// Precedence lists the sources of test types used, from the one which takes precedence:
+	// directive, tag and path. It defaults to ClassificationPrecedence.
+	Precedence []string `json:"precedence"`
+}
+
+// SkipIssueConfig lists the issue urls accepted by the skip_issue rule, instead of the ones of
+// DefaultIssuePattern. A url is accepted if it matches any pattern or tracker.
+type SkipIssueConfig struct {
+	// Patterns are regular expressions of issue urls.
+	Patterns []string `json:"patterns"`
+	// Trackers are named issue trackers, see IssueTrackerPattern.
+	Trackers []string `json:"trackers"`
+}
+
+// Apply configures the rules of LintRulesList, and the ones created by Registry, with the settings.
+func (c *Config) Apply() error {
+	if c.Classification.Precedence != nil {
+		if err := ValidatePrecedence(c.Classification.Precedence); err != nil {
+			return err
+		}
+		ClassificationPrecedence = c.Classification.Precedence
+	}
+	if len(c.SkipIssue.Patterns) == 0 && len(c.SkipIssue.Trackers) == 0 {
+		return nil
+	}
+	rule, err := c.SkipIssue.rule()
+	if err != nil {
+		return err
+	}
+	return setRule(rule)
+}
+
+// rule returns the SkipIssue rule accepting the urls of the patterns and trackers.
+func (c *SkipIssueConfig) rule() (*SkipIssue, error) {
+	patterns := append([]string{}, c.Patterns...)
+	for _, tracker := range c.Trackers {
+		p, err := IssueTrackerPattern(tracker)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	return NewSkipByIssuePatterns(patterns)
+}
+
+// setRule replaces the rules with the same ID as rule in LintRulesList and Registry by rule.
+func setRule(rule checker.Rule) error {
+	lists := make([][]checker.Rule, 0, len(LintRulesList))
+	for _, rules := range LintRulesList {
+		lists = append(lists, rules)
+	}
+	return Registry.SetRule(rule, lists...)
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// DuplicateCaseName requires that the cases of a table driven test have distinct and non empty
+// names, so that each subtest can be selected with go test -run. The cases are the elements of a
+// slice literal ranged over by a loop which runs a subtest named after a field of the case, as in
+// for _, tc := range cases { t.Run(tc.name, ...) }. Only the names given as string literals are
+// checked, and keyed cases which leave out the name field are reported as having an empty name.
+type DuplicateCaseName struct{}
+
+// NewDuplicateCaseName creates and returns a DuplicateCaseName object.
+func NewDuplicateCaseName() *DuplicateCaseName {
+	return &DuplicateCaseName{}
+}
+
+// GetID returns duplicate_case_name.
+func (lr *DuplicateCaseName) GetID() string {
+	return GetCallerFileName()
+}
+
+// GetSeverity returns warning.
+func (lr *DuplicateCaseName) GetSeverity() checker.Severity {
+	return checker.SeverityWarning
+}
+
+// Check verifies that the cases ranged over by aNode, if it is a range statement running a subtest
+// per case, have distinct and non empty names. If verification fails lrp creates a new report.
+func (lr *DuplicateCaseName) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	rs, ok := aNode.(*ast.RangeStmt)
+	if !ok {
+		return
+	}
+	value, ok := rs.Value.(*ast.Ident)
+	if !ok || value.Obj == nil {
+		return
+	}
+	field := subtestNameField(rs.Body, value.Obj)
+	cases := caseLiteral(rs.X)
+	if field == "" || cases == nil {
+		return
+	}
+
+	names := map[string]token.Position{}
+	for _, elt := range cases.Elts {
+		if u, ok := elt.(*ast.UnaryExpr); ok && u.Op == token.AND {
+			elt = u.X
+		}
+		lit, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		value, keyed := caseName(lit, field)
+		if value == nil {
+			// A keyed literal without the field has the zero value, an empty name.
+			if keyed {
+				lrp.AddItem(fs.Position(lit.Pos()), lr.GetID(), "Test case has an empty name.")
+			}
+			continue
+		}
+		name, ok := stringLiteral(value.Value)
+		if !ok {
+			continue
+		}
+		pos := fs.Position(value.Value.Pos())
+		if name == "" {
+			lrp.AddItem(pos, lr.GetID(), "Test case has an empty name.")
+		} else if first, ok := names[name]; ok {
+			lrp.AddItem(pos, lr.GetID(), fmt.Sprintf("Test case name %q is already used on line %d.", name, first.Line))
+		} else {
+			names[name] = pos
+		}
+	}
+}
+
+// caseName returns the element of the case literal lit setting field, or nil if there is none.
+// keyed is true if all the elements of lit are keyed, so that field is not set positionally.
+func caseName(lit *ast.CompositeLit, field string) (value *ast.KeyValueExpr, keyed bool) {
+	keyed = true
+	for _, e := range lit.Elts {
+		kv, ok := e.(*ast.KeyValueExpr)
+		if !ok {
+			keyed = false
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+			return kv, true
+		}
+	}
+	return nil, keyed
+}
+
+// subtestNameField returns the name of the field of the loop variable tc used as the name of a
+// subtest in body, as in t.Run(tc.name, ...), or an empty string if there is none.
+func subtestNameField(body *ast.BlockStmt, tc *ast.Object) string {
+	field := ""
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && len(call.Args) == 2 {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Run" && isTestingReceiver(sel.X) {
+				if name, ok := call.Args[0].(*ast.SelectorExpr); ok {
+					if id, ok := name.X.(*ast.Ident); ok && id.Obj == tc {
+						field = name.Sel.Name
+					}
+				}
+			}
+		}
+		return field == ""
+	})
+	return field
+}
+
+// caseLiteral returns the slice or array literal x, or the one x is declared with if it is a
+// variable of the file, or nil if there is none.
+func caseLiteral(x ast.Expr) *ast.CompositeLit {
+	if id, ok := x.(*ast.Ident); ok && id.Obj != nil {
+		switch decl := id.Obj.Decl.(type) {
+		case *ast.AssignStmt:
+			x = declaredValue(id.Name, decl.Lhs, decl.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, 0, len(decl.Names))
+			for _, name := range decl.Names {
+				lhs = append(lhs, name)
+			}
+			x = declaredValue(id.Name, lhs, decl.Values)
+		}
+	}
+	lit, ok := x.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	if _, ok := lit.Type.(*ast.ArrayType); !ok {
+		return nil
+	}
+	return lit
+}
+
+// declaredValue returns the value assigned to the variable name by a declaration of lhs with rhs.
+func declaredValue(name string, lhs, rhs []ast.Expr) ast.Expr {
+	if len(lhs) != len(rhs) {
+		return nil
+	}
+	for i, x := range lhs {
+		if id, ok := x.(*ast.Ident); ok && id.Name == name {
+			return rhs[i]
+		}
+	}
+	return nil
+}
+
+// stringLiteral returns the value of x if it is a string literal.
+func stringLiteral(x ast.Expr) (string, bool) {
+	lit, ok := x.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
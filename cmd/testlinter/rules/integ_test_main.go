@@ -0,0 +1,131 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// IntegTestMain requires that an integration test package has a TestMain function which sets up
+// the test framework, so that the environment of the tests is created and cleaned up, such as
+// a TestMain calling framework.NewSuite("galley", m).Run().
+type IntegTestMain struct {
+	framework string // Name the test framework package is imported as.
+}
+
+// NewIntegTestMain creates and returns an IntegTestMain object.
+func NewIntegTestMain() *IntegTestMain {
+	return &IntegTestMain{
+		framework: "framework",
+	}
+}
+
+// GetID returns integ_test_main.
+func (lr *IntegTestMain) GetID() string {
+	return GetCallerFileName()
+}
+
+// GetSeverity returns error.
+func (lr *IntegTestMain) GetSeverity() checker.Severity {
+	return checker.SeverityError
+}
+
+// Check does nothing, the rule checks whole packages.
+func (lr *IntegTestMain) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+}
+
+// CheckPackage verifies that a package with tests has a TestMain function calling the test
+// framework. The internal and external test packages of a directory are one test binary, so a
+// TestMain in the other package clause of the directory sets up the framework for both. If
+// verification fails lrp creates a new report.
+func (lr *IntegTestMain) CheckPackage(files []*ast.File, fs *token.FileSet, lrp *checker.Report) {
+	var firstTest *ast.File
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			if isTestMain(fn) {
+				if !lr.callsFramework(fn) {
+					lrp.AddItem(fs.Position(fn.Pos()), lr.GetID(),
+						"TestMain should set up the test framework, e.g. framework.NewSuite(m).Run().")
+				}
+				return
+			}
+			if firstTest == nil && strings.HasPrefix(fn.Name.Name, "Test") {
+				firstTest = f
+			}
+		}
+	}
+	if firstTest != nil && !otherHasTestMain(files[0], fs) {
+		lrp.AddItem(fs.Position(firstTest.Package), lr.GetID(),
+			"Integration test package has no TestMain setting up the test framework.")
+	}
+}
+
+// isTestMain returns true if fn is a TestMain function.
+func isTestMain(fn *ast.FuncDecl) bool {
+	return fn.Recv == nil && fn.Name.Name == "TestMain"
+}
+
+// otherHasTestMain returns true if a test file in the directory of file has a package clause
+// other than the one of file, and a TestMain function. That TestMain is checked with the files of
+// its own package.
+func otherHasTestMain(file *ast.File, fs *token.FileSet) bool {
+	dir := filepath.Dir(fs.Position(file.Package).Filename)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, info.Name()), nil, 0)
+		if err != nil || f.Name.Name == file.Name.Name {
+			continue
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && isTestMain(fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// callsFramework returns true if fn calls a function of the test framework package.
+func (lr *IntegTestMain) callsFramework(fn *ast.FuncDecl) bool {
+	found := false
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if ce, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := ce.Fun.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == lr.framework {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
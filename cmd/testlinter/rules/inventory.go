@@ -0,0 +1,221 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// Inventory lists the test functions of the test files it is the RulesFactory of, with their
+// test type and how they are skipped.
+type Inventory struct {
+	issueRegex *regexp.Regexp
+
+	mu    sync.Mutex
+	tests []TestInfo
+}
+
+// TestInfo describes a test function of the inventory.
+type TestInfo struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Name string `json:"name"`
+	// Type is the test type of the file: unit, integration or e2e.
+	Type string `json:"type"`
+	// Short is true if the test checks testing.Short(), to skip itself or part of it in short mode.
+	Short bool `json:"short"`
+	// Skipped is true if the test skips itself, other than in short mode.
+	Skipped bool `json:"skipped"`
+	// Skips are the calls skipping the test, other than in short mode.
+	Skips []SkipInfo `json:"skips,omitempty"`
+}
+
+// SkipInfo describes a call skipping a test, such as t.Skip().
+type SkipInfo struct {
+	Line int `json:"line"`
+	// Call is the method called: Skip, Skipf or SkipNow.
+	Call string `json:"call"`
+	// Issue is the url of the issue referenced by the call, as accepted by the skip_issue rule.
+	Issue string `json:"issue,omitempty"`
+	// IssueNumber is the last element of the issue url, such as 6012 or MESH-42.
+	IssueNumber string `json:"issue_number,omitempty"`
+}
+
+// NewInventory returns an empty inventory, which recognises the issue urls accepted by the
+// skip_issue rule of Registry.
+func NewInventory() *Inventory {
+	pattern := DefaultIssuePattern
+	if info, ok := Registry.Lookup(NewSkipByIssue().GetID()); ok {
+		if rule, ok := info.New().(*SkipIssue); ok {
+			pattern = rule.skipArgsRegex
+		}
+	}
+	return &Inventory{issueRegex: regexp.MustCompile(pattern)}
+}
+
+// GetRules returns the rule adding the tests of absp to the inventory if it is a test file.
+func (inv *Inventory) GetRules(absp string, info os.FileInfo) []checker.Rule {
+	testType, ok := GetTestType(absp, info)
+	if !ok {
+		return []checker.Rule{}
+	}
+	return []checker.Rule{&inventoryRule{inv: inv, testType: testType}}
+}
+
+// Tests returns the tests of the inventory, sorted by file and line.
+func (inv *Inventory) Tests() []TestInfo {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	tests := append([]TestInfo{}, inv.tests...)
+	sort.Slice(tests, func(i, j int) bool {
+		if tests[i].File != tests[j].File {
+			return tests[i].File < tests[j].File
+		}
+		return tests[i].Line < tests[j].Line
+	})
+	return tests
+}
+
+// WriteJSON writes the tests of the inventory to w as a JSON array.
+func (inv *Inventory) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(inv.Tests(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteCSV writes the tests of the inventory to w as CSV, with a header row. The lines and
+// issues of the calls skipping a test are separated by spaces.
+func (inv *Inventory) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"file", "line", "name", "type", "short", "skipped", "skip_lines", "issues", "issue_numbers"}}
+	for _, t := range inv.Tests() {
+		var lines, issues, numbers []string
+		for _, s := range t.Skips {
+			lines = append(lines, strconv.Itoa(s.Line))
+			if s.Issue != "" {
+				issues = append(issues, s.Issue)
+				numbers = append(numbers, s.IssueNumber)
+			}
+		}
+		rows = append(rows, []string{t.File, strconv.Itoa(t.Line), t.Name, t.Type, strconv.FormatBool(t.Short),
+			strconv.FormatBool(t.Skipped), strings.Join(lines, " "), strings.Join(issues, " "), strings.Join(numbers, " ")})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("unable to write the inventory: %v", err)
+	}
+	return nil
+}
+
+func (inv *Inventory) add(tests []TestInfo) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.tests = append(inv.tests, tests...)
+}
+
+// inventoryRule adds the tests of the files it checks to an inventory. It reports no finding.
+type inventoryRule struct {
+	inv      *Inventory
+	testType TestType
+}
+
+// GetID returns inventory.
+func (lr *inventoryRule) GetID() string {
+	return GetCallerFileName()
+}
+
+// Check adds the test functions of aNode to the inventory if it is a file.
+func (lr *inventoryRule) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	file, ok := aNode.(*ast.File)
+	if !ok {
+		return
+	}
+	var tests []TestInfo
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && isTestFunc(fn) {
+			tests = append(tests, lr.testInfo(fn, fs))
+		}
+	}
+	lr.inv.add(tests)
+}
+
+// testInfo returns the inventory entry of the test function fn.
+func (lr *inventoryRule) testInfo(fn *ast.FuncDecl, fs *token.FileSet) TestInfo {
+	pos := fs.Position(fn.Pos())
+	test := TestInfo{File: pos.Filename, Line: pos.Line, Name: fn.Name.Name, Type: lr.testType.String()}
+	isShort := func(call *ast.CallExpr) bool {
+		return MatchCallExpr(call, "testing", "Short")
+	}
+	shortSkips := shortSkipCalls(fn.Body, isShort)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if isShort(call) {
+			test.Short = true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || shortSkips[call] || !isTestingReceiver(sel.X) {
+			return true
+		}
+		switch sel.Sel.Name {
+		case "Skip", "Skipf", "SkipNow":
+			skip := SkipInfo{Line: fs.Position(call.Pos()).Line, Call: sel.Sel.Name}
+			if len(call.Args) > 0 {
+				if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					if skip.Issue = lr.inv.issueRegex.FindString(lit.Value); skip.Issue != "" {
+						skip.IssueNumber = path.Base(skip.Issue)
+					}
+				}
+			}
+			test.Skipped = true
+			test.Skips = append(test.Skips, skip)
+		}
+		return true
+	})
+	return test
+}
+
+// isTestFunc returns true if fn is a test function, such as func TestXxx(t *testing.T).
+func isTestFunc(fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || fn.Body == nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+		return false
+	}
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) > 1 {
+		return false
+	}
+	if star, ok := params[0].Type.(*ast.StarExpr); ok {
+		return isTestingType(star) && star.X.(*ast.SelectorExpr).Sel.Name == "T"
+	}
+	return false
+}
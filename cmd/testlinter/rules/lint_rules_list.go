@@ -12,23 +12,30 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package rules
 
 import (
-	"istio.io/tools/cmd/testlinter/rules"
 	"istio.io/tools/pkg/checker"
 )
 
 // LintRulesList is a map that maps test type to list of lint rules. Linter applies corresponding
-// list of lint rules to each type of tests.
+// list of lint rules to each type of tests. It can be overridden per test type by the rules section
+// of the config file, see ConfigureRules.
 var LintRulesList = map[TestType][]checker.Rule{
 	UnitTest: { // list of rules which should apply to unit test file
-		rules.NewSkipByIssue(),
+		NewSkipByIssue(),
+		NewNoSleep(),
+		NewNoGoroutine(),
+		NewTestTypeConflict(),
 	},
 	IntegTest: { // list of rules which should apply to integration test file
-		rules.NewSkipByIssue(),
+		NewSkipByIssue(),
+		NewSkipByShort(),
+		NewTestTypeConflict(),
 	},
 	E2eTest: { // list of rules which should apply to e2e test file
-		rules.NewSkipByIssue(),
+		NewSkipByIssue(),
+		NewSkipByShort(),
+		NewTestTypeConflict(),
 	},
 }
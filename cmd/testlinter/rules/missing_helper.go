@@ -0,0 +1,104 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// failureMethods are the methods of *testing.T, *testing.B and testing.TB reporting a failure at
+// the line of their caller.
+var failureMethods = map[string]bool{"Error": true, "Errorf": true, "Fatal": true, "Fatalf": true}
+
+// MissingHelper requires that a test helper, a function which is not a test but takes a *testing.T,
+// *testing.B or testing.TB parameter, calls t.Helper() if it reports failures with t.Fatal(),
+// t.Fatalf(), t.Error() or t.Errorf(). Otherwise failures are reported at the line of the helper
+// rather than at the line of the test calling it.
+type MissingHelper struct{}
+
+// NewMissingHelper creates and returns a MissingHelper object.
+func NewMissingHelper() *MissingHelper {
+	return &MissingHelper{}
+}
+
+// GetID returns missing_helper.
+func (lr *MissingHelper) GetID() string {
+	return GetCallerFileName()
+}
+
+// GetSeverity returns info.
+func (lr *MissingHelper) GetSeverity() checker.Severity {
+	return checker.SeverityInfo
+}
+
+// Check verifies that aNode calls t.Helper() if it is a helper reporting failures with its
+// testing parameter t. If verification fails lrp creates a new report, with a fix adding the call.
+func (lr *MissingHelper) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	fn, ok := aNode.(*ast.FuncDecl)
+	if !ok || fn.Body == nil || isTestFunc(fn) || isBenchmarkFunc(fn) {
+		return
+	}
+	for _, field := range fn.Type.Params.List {
+		if !isTestingType(field.Type) {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Obj == nil || name.Name == "_" {
+				continue
+			}
+			failure, helper := testingCalls(fn.Body, name.Obj)
+			if failure != "" && !helper {
+				fix := checker.NewTextEdit(fs, fn.Body.Lbrace+1, fn.Body.Lbrace+1, "\n"+name.Name+".Helper()\n")
+				lrp.AddItemWithFix(fs.Position(fn.Pos()), lr.GetID(), fmt.Sprintf(
+					"Helper %s calls %s.%s() but not %s.Helper(), so failures are reported at the wrong line.",
+					fn.Name.Name, name.Name, failure, name.Name), fix)
+			}
+		}
+	}
+}
+
+// testingCalls returns the first failure method called on t in body, outside of function
+// literals, and whether t.Helper() is called.
+func testingCalls(body *ast.BlockStmt, t *ast.Object) (failure string, helper bool) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok && id.Obj == t {
+					if sel.Sel.Name == "Helper" {
+						helper = true
+					} else if failureMethods[sel.Sel.Name] && failure == "" {
+						failure = sel.Sel.Name
+					}
+				}
+			}
+		}
+		return true
+	})
+	return failure, helper
+}
+
+// isBenchmarkFunc returns true if fn is a benchmark function, such as func BenchmarkXxx(b *testing.B).
+func isBenchmarkFunc(fn *ast.FuncDecl) bool {
+	params := fn.Type.Params.List
+	return fn.Recv == nil && len(params) == 1 && strings.HasPrefix(fn.Name.Name, "Benchmark") && isTestingType(params[0].Type)
+}
@@ -34,6 +34,11 @@ func (lr *NoGoroutine) GetID() string {
 	return GetCallerFileName()
 }
 
+// GetSeverity returns warning.
+func (lr *NoGoroutine) GetSeverity() checker.Severity {
+	return checker.SeverityWarning
+}
+
 // Check verifies if aNode is not goroutine. If verification fails lrp creates new report.
 func (lr *NoGoroutine) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
 	if gs, ok := aNode.(*ast.GoStmt); ok {
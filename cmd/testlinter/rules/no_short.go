@@ -34,6 +34,11 @@ func (lr *NoShort) GetID() string {
 	return GetCallerFileName()
 }
 
+// GetSeverity returns error.
+func (lr *NoShort) GetSeverity() checker.Severity {
+	return checker.SeverityError
+}
+
 // Check verifies if aNode is not testing.Short(). If verification lrp creates new report.
 func (lr *NoShort) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
 	if ce, ok := aNode.(*ast.CallExpr); ok {
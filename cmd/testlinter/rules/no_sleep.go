@@ -17,6 +17,7 @@ package rules
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 
 	"istio.io/tools/pkg/checker"
 )
@@ -34,11 +35,38 @@ func (lr *NoSleep) GetID() string {
 	return GetCallerFileName()
 }
 
-// Check verifies if aNode is not time.Sleep. If verification fails lrp creates a new report.
+// GetSeverity returns warning.
+func (lr *NoSleep) GetSeverity() checker.Severity {
+	return checker.SeverityWarning
+}
+
+// Check verifies there are no calls to time.Sleep in aNode if it is a file, including through
+// renamed imports of time. If verification fails lrp creates a new report.
 func (lr *NoSleep) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	file, ok := aNode.(*ast.File)
+	if !ok {
+		return
+	}
+	names := importNames(file, "time")
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ce, ok := n.(*ast.CallExpr); ok && matchImportedCall(ce, names, "Sleep") {
+			lrp.AddItem(fs.Position(ce.Pos()), lr.GetID(), "time.Sleep() is disallowed.")
+		}
+		return true
+	})
+}
+
+// CheckTyped verifies if aNode is not time.Sleep, including through dot imports of time. If verification fails lrp creates a new report.
+func (lr *NoSleep) CheckTyped(aNode ast.Node, fs *token.FileSet, info *types.Info, _ *types.Package, lrp *checker.Report) {
 	if ce, ok := aNode.(*ast.CallExpr); ok {
-		if MatchCallExpr(ce, "time", "Sleep") {
+		if checker.MatchCallFunc(info, ce, "time", "Sleep") {
 			lrp.AddItem(fs.Position(ce.Pos()), lr.GetID(), "time.Sleep() is disallowed.")
 		}
 	}
 }
+
+// TypesOptional returns true, as Check recognises time.Sleep() without type information. Types
+// are only loaded for the rule with -types.
+func (lr *NoSleep) TypesOptional() bool {
+	return true
+}
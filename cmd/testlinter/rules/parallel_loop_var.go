@@ -0,0 +1,133 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// ParallelLoopVar requires that a parallel subtest does not capture a range loop variable, as
+// all the subtests would see its last value once they resume. For example,
+// for _, tc := range cases { t.Run(tc.name, func(t *testing.T) { t.Parallel(); check(tc) }) }
+// is reported, while rebinding the variable with tc := tc before t.Run, or at the top of the
+// subtest before t.Parallel(), is valid.
+type ParallelLoopVar struct{}
+
+// NewParallelLoopVar creates and returns a ParallelLoopVar object.
+func NewParallelLoopVar() *ParallelLoopVar {
+	return &ParallelLoopVar{}
+}
+
+// GetID returns parallel_loop_var.
+func (lr *ParallelLoopVar) GetID() string {
+	return GetCallerFileName()
+}
+
+// GetSeverity returns error.
+func (lr *ParallelLoopVar) GetSeverity() checker.Severity {
+	return checker.SeverityError
+}
+
+// Check verifies that the subtests run in the body of aNode, if it is a range statement, do not
+// capture its loop variables when they call t.Parallel(). If verification fails lrp creates a new
+// report.
+func (lr *ParallelLoopVar) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	rs, ok := aNode.(*ast.RangeStmt)
+	if !ok || rs.Tok != token.DEFINE {
+		return
+	}
+	loopVars := map[*ast.Object]bool{}
+	for _, x := range []ast.Expr{rs.Key, rs.Value} {
+		if id, ok := x.(*ast.Ident); ok && id.Obj != nil {
+			loopVars[id.Obj] = true
+		}
+	}
+	if len(loopVars) == 0 {
+		return
+	}
+	ast.Inspect(rs.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Run" || len(call.Args) != 2 || !isTestingReceiver(sel.X) {
+			return true
+		}
+		if fn, ok := call.Args[1].(*ast.FuncLit); ok && callsParallel(fn) {
+			reported := map[*ast.Object]bool{}
+			rebound := reboundIdents(fn.Body, loopVars)
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && loopVars[id.Obj] && !rebound[id] && !reported[id.Obj] {
+					reported[id.Obj] = true
+					lrp.AddItem(fs.Position(id.Pos()), lr.GetID(), fmt.Sprintf(
+						"Loop variable %s is captured by a parallel subtest, rebind it with %s := %s before t.Run().",
+						id.Name, id.Name, id.Name))
+				}
+				return true
+			})
+		}
+		return true
+	})
+}
+
+// reboundIdents returns the loop variables read by the shadowing declarations, such as tc := tc,
+// which start body. They copy the value before the subtest calls t.Parallel() and its body reads
+// the copy instead.
+func reboundIdents(body *ast.BlockStmt, loopVars map[*ast.Object]bool) map[*ast.Ident]bool {
+	rebound := map[*ast.Ident]bool{}
+	for _, stmt := range body.List {
+		as, ok := stmt.(*ast.AssignStmt)
+		if !ok || as.Tok != token.DEFINE || len(as.Lhs) != len(as.Rhs) {
+			break
+		}
+		for i, x := range as.Rhs {
+			id, ok := x.(*ast.Ident)
+			if !ok || !loopVars[id.Obj] {
+				continue
+			}
+			if lhs, ok := as.Lhs[i].(*ast.Ident); ok && lhs.Name == id.Name {
+				rebound[id] = true
+			}
+		}
+	}
+	return rebound
+}
+
+// callsParallel returns true if the body of the subtest fn calls Parallel() on its *testing.T
+// parameter.
+func callsParallel(fn *ast.FuncLit) bool {
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) != 1 || params[0].Names[0].Obj == nil {
+		return false
+	}
+	param := params[0].Names[0].Obj
+	parallel := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Parallel" {
+				if id, ok := sel.X.(*ast.Ident); ok && id.Obj == param {
+					parallel = true
+				}
+			}
+		}
+		return !parallel
+	})
+	return parallel
+}
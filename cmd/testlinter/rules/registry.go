@@ -0,0 +1,138 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"io"
+	"os"
+	"sort"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// allTestTypes are the names of all test types, for the rules that can apply to any test.
+var allTestTypes = []string{UnitTest.String(), IntegTest.String(), E2eTest.String()}
+
+// Registry holds all the rules of testlinter, including the ones that are not in LintRulesList,
+// so that they can be listed and enabled by name.
+var Registry = checker.NewRegistry(allTestTypes,
+	checker.RuleInfo{
+		Description: "Tests must only be skipped with t.Skip() or t.Skipf() and a url to an issue of the configured trackers.",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewSkipByIssue() },
+	},
+	checker.RuleInfo{
+		Description: "Tests must be skipped when testing.Short() is true.",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewSkipByShort() },
+	},
+	checker.RuleInfo{
+		Description: "Tests must not call testing.Short().",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewNoShort() },
+	},
+	checker.RuleInfo{
+		Description: "Tests must not call time.Sleep().",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewNoSleep() },
+	},
+	checker.RuleInfo{
+		Description: "Tests must not start goroutines.",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewNoGoroutine() },
+	},
+	checker.RuleInfo{
+		Description: "The +testtype directive, build constraints and path of a test file must give the same test type.",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewTestTypeConflict() },
+	},
+	checker.RuleInfo{
+		Description: "Parallel subtests must not capture range loop variables.",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewParallelLoopVar() },
+	},
+	checker.RuleInfo{
+		Description: "The cases of table driven tests must have distinct and non empty names.",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewDuplicateCaseName() },
+	},
+	checker.RuleInfo{
+		Description: "Test helpers reporting failures must call t.Helper().",
+		Kinds:       allTestTypes,
+		New:         func() checker.Rule { return NewMissingHelper() },
+	},
+	checker.RuleInfo{
+		Description: "Integration test packages must have a TestMain setting up the test framework.",
+		Kinds:       []string{IntegTest.String()},
+		New:         func() checker.Rule { return NewIntegTestMain() },
+	},
+)
+
+// AllRules returns a RulesFactory which returns all the rules of Registry that can apply to the test
+// type of each test file, including the rules which are not in LintRulesList.
+func AllRules() checker.RulesFactory {
+	return Registry.AllRules(func(absp string, info os.FileInfo) (string, bool) {
+		testType, ok := GetTestType(absp, info)
+		return testType.String(), ok
+	})
+}
+
+// ConfigureRules replaces the rules of LintRulesList with the ones listed for their test type in
+// policy, the rules section of a config file. Test types which are not in policy keep their rules.
+func ConfigureRules(policy map[string][]string) error {
+	names := make([]string, 0, len(policy))
+	for name := range policy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		selected, err := Registry.NewRules(name, policy[name])
+		if err != nil {
+			return err
+		}
+		for _, t := range TestTypes {
+			if t.String() == name {
+				LintRulesList[t] = selected
+			}
+		}
+	}
+	return nil
+}
+
+// SelectRules applies rules selected by the -enable and -disable flags, lists of rule_id or
+// test_type:rule_id, to LintRulesList.
+func SelectRules(enable, disable string) error {
+	enabled, err := Registry.ParseSelectors(enable)
+	if err != nil {
+		return err
+	}
+	disabled, err := Registry.ParseSelectors(disable)
+	if err != nil {
+		return err
+	}
+	for _, t := range TestTypes {
+		LintRulesList[t] = Registry.Select(t.String(), LintRulesList[t], enabled, disabled)
+	}
+	return nil
+}
+
+// WriteRules writes the table of all rules, and the test types they are enabled for, to w.
+func WriteRules(w io.Writer) error {
+	enabled := map[string][]checker.Rule{}
+	for _, t := range TestTypes {
+		enabled[t.String()] = LintRulesList[t]
+	}
+	return Registry.WriteRules(w, enabled)
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package rules
 
 import (
 	"os"
@@ -31,12 +31,40 @@ const (
 	E2eTest   TestType = iota // E2eTest == 2
 )
 
+// TestTypes are all the types of tests, in the order of their IDs.
+var TestTypes = []TestType{UnitTest, IntegTest, E2eTest}
+
+// testTypeNames are the names of the test types, as used by the -enable and -disable flags.
+var testTypeNames = map[TestType]string{
+	UnitTest:  "unit",
+	IntegTest: "integration",
+	E2eTest:   "e2e",
+}
+
+// String returns the name of the test type: unit, integration or e2e.
+func (t TestType) String() string {
+	return testTypeNames[t]
+}
+
 // RulesMatcher filters out test files and detects test type.
 type RulesMatcher struct {
 }
 
-// GetRules checks path absp and decides whether absp is a test file. It returns true and test type
+// GetRules checks path absp and returns the rules of its test type, or no rules if absp is not a
+// test file, see GetTestType.
+func (rf *RulesMatcher) GetRules(absp string, info os.FileInfo) []checker.Rule {
+	testType, ok := GetTestType(absp, info)
+	if !ok {
+		return []checker.Rule{}
+	}
+	return LintRulesList[testType]
+}
+
+// GetTestType checks path absp and decides whether absp is a test file. It returns true and test type
 // for a test file. If path absp should be skipped, it returns false.
+// The test type is given by the first of the sources of ClassificationPrecedence which gives
+// one: a // +testtype=<type> directive before the package clause, a build constraint requiring
+// the integ, integration or e2e tag, or the path of the file.
 // If one of the following cases meet, path absp is a valid path to test file.
 // (1) e2e test file
 // .../e2e/.../*_test.go
@@ -46,23 +74,31 @@ type RulesMatcher struct {
 // .../*_integ_test.go
 // (3) unit test file
 // .../*_test.go
-func (rf *RulesMatcher) GetRules(absp string, info os.FileInfo) []checker.Rule {
+func GetTestType(absp string, info os.FileInfo) (TestType, bool) {
 	// Skip path which is not go test file or is a directory.
-	paths := strings.Split(absp, "/")
-	if len(paths) == 0 || info.IsDir() || !strings.HasSuffix(absp, "_test.go") {
-		return []checker.Rule{}
+	if info.IsDir() || !strings.HasSuffix(absp, "_test.go") {
+		return UnitTest, false
 	}
+	// Files which cannot be read are classified by their path, and fail to be checked later.
+	header, _ := readHeader(absp)
+	sources, _ := testTypeSources(absp, header)
+	return classify(sources), true
+}
 
+// pathTestType returns the test type given by the path of test file absp, if it is in an e2e or
+// integration directory or has the _integ_test.go suffix.
+func pathTestType(absp string) (TestType, bool) {
+	paths := strings.Split(absp, "/")
 	for _, path := range paths {
 		if path == "e2e" {
-			return LintRulesList[E2eTest]
+			return E2eTest, true
 		} else if path == "integration" {
-			return LintRulesList[IntegTest]
+			return IntegTest, true
 		}
 	}
 	if strings.HasSuffix(paths[len(paths)-1], "_integ_test.go") {
 		// Integration tests can be in non integration directories.
-		return LintRulesList[IntegTest]
+		return IntegTest, true
 	}
-	return LintRulesList[UnitTest]
+	return UnitTest, false
 }
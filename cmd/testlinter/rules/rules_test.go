@@ -0,0 +1,264 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"istio.io/tools/pkg/checker"
+	"istio.io/tools/pkg/checker/checkertest"
+)
+
+func TestSkipIssue(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewSkipByIssue(), "skip_issue/default")
+}
+
+func TestSkipIssueNested(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewSkipByIssue(), "skip_issue/nested")
+	checkertest.RunWithOptions(t, checkertest.TestData(), NewSkipByIssue(), checker.Options{LoadTypes: true}, "skip_issue/nested")
+}
+
+func TestSkipIssueTrackers(t *testing.T) {
+	c := SkipIssueConfig{
+		Patterns: []string{`https://git\.example\.com/mesh/[\w-]+/-/issues/[0-9]+`},
+		Trackers: []string{"github:istio/api", "github:istio-ecosystem", "jira:https://issues.example.com/"},
+	}
+	rule, err := c.rule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkertest.Run(t, checkertest.TestData(), rule, "skip_issue/trackers")
+}
+
+func TestIssueTrackerPatternErrors(t *testing.T) {
+	for _, tracker := range []string{"github", "github:", "github:a/b/c", "gitlab:istio", "jira:"} {
+		if _, err := IssueTrackerPattern(tracker); err == nil {
+			t.Errorf("expected an error for %q", tracker)
+		}
+	}
+	if _, err := NewSkipByIssuePatterns([]string{"("}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestShortSkip(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewSkipByShort(), "short_skip")
+}
+
+func TestNoShort(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewNoShort(), "no_short")
+}
+
+func TestNoSleep(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewNoSleep(), "no_sleep")
+	checkertest.RunWithOptions(t, checkertest.TestData(), NewNoSleep(), checker.Options{LoadTypes: true}, "no_sleep")
+}
+
+func TestNoGoroutine(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewNoGoroutine(), "no_goroutine")
+}
+
+func TestParallelLoopVar(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewParallelLoopVar(), "parallel_loop_var")
+}
+
+func TestDuplicateCaseName(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewDuplicateCaseName(), "duplicate_case_name")
+}
+
+func TestMissingHelper(t *testing.T) {
+	report := checkertest.Run(t, checkertest.TestData(), NewMissingHelper(), "missing_helper")
+
+	var diff bytes.Buffer
+	if err := checker.ApplyFixes(report, &diff); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"+\tt.Helper()\n", "+\ttb.Helper()\n"} {
+		if !strings.Contains(diff.String(), line) {
+			t.Errorf("fixes don't add %q\n%s", line, diff.String())
+		}
+	}
+}
+
+func TestIntegTestMain(t *testing.T) {
+	checkertest.Run(t, checkertest.TestData(), NewIntegTestMain(), "integ_test_main")
+}
+
+func TestLintRulesRegistered(t *testing.T) {
+	for _, tt := range TestTypes {
+		for _, rule := range LintRulesList[tt] {
+			info, ok := Registry.Lookup(rule.GetID())
+			if !ok {
+				t.Errorf("rule %s is not registered", rule.GetID())
+				continue
+			}
+			if _, err := Registry.ParseSelectors(tt.String() + ":" + info.ID); err != nil {
+				t.Errorf("rule %s is applied to %s tests: %v", info.ID, tt, err)
+			}
+		}
+	}
+}
+
+func TestLintRulesCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testlinter-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cache := checker.NewCache(dir, "test")
+	check := func() []string {
+		report := checker.NewLintReport()
+		opts := checker.Options{LoadTypes: true, Cache: cache}
+		paths := []string{filepath.Join(checkertest.TestData(), "inventory")}
+		if err := checker.CheckWithOptions(paths, &RulesMatcher{}, checker.NewWhitelist(nil), report, opts); err != nil {
+			t.Fatal(err)
+		}
+		return report.Items()
+	}
+
+	first := check()
+	if hits, misses := cache.Stats(); hits != 0 || misses != 2 {
+		t.Fatalf("expected both files to be checked, received %d hits and %d misses", hits, misses)
+	}
+	second := check()
+	if hits, misses := cache.Stats(); hits != 2 || misses != 2 {
+		t.Errorf("expected both files to be served from the cache, received %d hits and %d misses", hits, misses)
+	}
+	if !reflect.DeepEqual(second, first) {
+		t.Errorf("cached findings %v differ from %v", second, first)
+	}
+}
+
+func TestConfigureRules(t *testing.T) {
+	defaults := map[TestType][]checker.Rule{}
+	for tt, rules := range LintRulesList {
+		defaults[tt] = rules
+	}
+	defer func() { LintRulesList = defaults }()
+
+	policy := map[string][]string{"unit": {"no_short", "skip_issue"}, "e2e": {}}
+	if err := ConfigureRules(policy); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[TestType][]string{
+		UnitTest:  {"no_short", "skip_issue"},
+		IntegTest: {"skip_issue", "short_skip", "test_type_conflict"},
+		E2eTest:   {},
+	}
+	for tt, ids := range expected {
+		got := []string{}
+		for _, rule := range LintRulesList[tt] {
+			got = append(got, rule.GetID())
+		}
+		if !reflect.DeepEqual(got, ids) {
+			t.Errorf("%s rules are %v, expected %v", tt, got, ids)
+		}
+	}
+
+	if err := ConfigureRules(map[string][]string{"unit": {"integ_test_main"}}); err == nil {
+		t.Error("expected an error for integ_test_main on unit tests")
+	}
+}
+
+func TestInventory(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join(checkertest.TestData(), "inventory"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inventory := NewInventory()
+	if err := checker.Check([]string{dir}, inventory, checker.NewWhitelist(nil), checker.NewLintReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	integ := filepath.Join(dir, "integration", "integration_test.go")
+	unit := filepath.Join(dir, "unit_test.go")
+	expected := []TestInfo{
+		{File: integ, Line: 21, Name: "TestIntegration", Type: "integration", Skipped: true, Skips: []SkipInfo{
+			{Line: 22, Call: "Skip", Issue: "https://github.com/istio/istio/issues/1", IssueNumber: "1"},
+			{Line: 23, Call: "SkipNow"},
+		}},
+		{File: unit, Line: 21, Name: "TestSkipped", Type: "unit", Skipped: true, Skips: []SkipInfo{
+			{Line: 23, Call: "Skipf", Issue: "https://github.com/istio/istio/issues/6012", IssueNumber: "6012"},
+		}},
+		{File: unit, Line: 27, Name: "TestShort", Type: "unit", Short: true},
+		{File: unit, Line: 33, Name: "TestRuns", Type: "unit"},
+	}
+	if tests := inventory.Tests(); !reflect.DeepEqual(tests, expected) {
+		t.Errorf("inventory doesn't match\nReceived: %+v\nExpected: %+v", tests, expected)
+	}
+
+	var out bytes.Buffer
+	if err := inventory.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expectedRows := []string{
+		"file,line,name,type,short,skipped,skip_lines,issues,issue_numbers",
+		integ + ",21,TestIntegration,integration,false,true,22 23,https://github.com/istio/istio/issues/1,1",
+	}
+	if len(rows) != 5 || !reflect.DeepEqual(rows[:2], expectedRows) {
+		t.Errorf("inventory CSV doesn't match\nReceived: %v\nExpected: %v followed by 3 rows", rows, expectedRows)
+	}
+}
+
+func TestTestTypeConflict(t *testing.T) {
+	opts := checker.Options{OptionalTags: TestTypeTags()}
+	checkertest.RunWithOptions(t, checkertest.TestData(), NewTestTypeConflict(), opts, "test_type_conflict")
+
+	defer func(precedence []string) { ClassificationPrecedence = precedence }(ClassificationPrecedence)
+	ClassificationPrecedence = []string{PathSource, TagSource}
+	checkertest.RunWithOptions(t, checkertest.TestData(), NewTestTypeConflict(), opts, "test_type_conflict_precedence")
+}
+
+func TestGetTestType(t *testing.T) {
+	defer func(precedence []string) { ClassificationPrecedence = precedence }(ClassificationPrecedence)
+
+	dir := filepath.Join(checkertest.TestData(), "test_type_conflict")
+	cases := []struct {
+		precedence []string
+		file       string
+		expected   TestType
+	}{
+		{[]string{DirectiveSource, TagSource, PathSource}, "e2e/tagged_test.go", IntegTest},
+		{[]string{DirectiveSource, TagSource, PathSource}, "directive_test.go", E2eTest},
+		{[]string{DirectiveSource, TagSource, PathSource}, "unknown_test.go", UnitTest},
+		{[]string{PathSource, TagSource}, "e2e/tagged_test.go", E2eTest},
+		{[]string{TagSource, DirectiveSource}, "directive_test.go", IntegTest},
+		{[]string{PathSource}, "directive_test.go", UnitTest},
+	}
+	for _, c := range cases {
+		ClassificationPrecedence = c.precedence
+		path := filepath.Join(dir, c.file)
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testType, ok := GetTestType(path, info); !ok || testType != c.expected {
+			t.Errorf("%s with precedence %v is a %s test, expected %s", c.file, c.precedence, testType, c.expected)
+		}
+	}
+
+	for _, precedence := range [][]string{{"tags"}, {PathSource, PathSource}} {
+		if err := ValidatePrecedence(precedence); err == nil {
+			t.Errorf("expected an error for precedence %v", precedence)
+		}
+	}
+}
@@ -17,26 +17,27 @@ package rules
 import (
 	"go/ast"
 	"go/token"
-	"strings"
 
 	"istio.io/tools/pkg/checker"
 )
 
 // ShortSkip requires that a test function should have one of these pattern.
 // Pattern 1
-// func TestA(t *testing.T) {
-//   if !testing.Short() {
-//    ...
-//   }
-// }
+//
+//	func TestA(t *testing.T) {
+//	  if !testing.Short() {
+//	   ...
+//	  }
+//	}
 //
 // Pattern 2
-// func TestB(t *testing.T) {
-//   if testing.Short() {
-//     t.Skip("xxx")
-//   }
-//   ...
-// }
+//
+//	func TestB(t *testing.T) {
+//	  if testing.Short() {
+//	    t.Skip("xxx")
+//	  }
+//	  ...
+//	}
 type ShortSkip struct{}
 
 // NewSkipByShort creates and returns a ShortSkip object.
@@ -49,50 +50,64 @@ func (lr *ShortSkip) GetID() string {
 	return GetCallerFileName()
 }
 
+// GetSeverity returns error.
+func (lr *ShortSkip) GetSeverity() checker.Severity {
+	return checker.SeverityError
+}
+
 // Check verifies if aNode is a valid t.Skip(). If verification fails lrp creates a new report.
 // There are two examples for valid t.Skip().
 // case 1:
-// func Testxxx(t *testing.T) {
-// 	if !testing.Short() {
-// 	...
-// 	}
-// }
+//
+//	func Testxxx(t *testing.T) {
+//		if !testing.Short() {
+//		...
+//		}
+//	}
+//
 // case 2:
-// func Testxxx(t *testing.T) {
-// 	if testing.Short() {
-//		t.Skip("xxx")
+//
+//	func Testxxx(t *testing.T) {
+//		if testing.Short() {
+//			t.Skip("xxx")
+//		}
+//		...
 //	}
-//	...
-// }
 func (lr *ShortSkip) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
-	if fn, isFn := aNode.(*ast.FuncDecl); isFn && strings.HasPrefix(fn.Name.Name, "Test") {
-		if len(fn.Body.List) == 0 {
-			lrp.AddItem(fs.Position(aNode.Pos()), lr.GetID(), "Missing either 'if testing.Short() { t.Skip() }' or 'if !testing.Short() {}'")
-		} else if len(fn.Body.List) == 1 {
-			if ifStmt, ok := fn.Body.List[0].(*ast.IfStmt); ok {
-				if uExpr, ok := ifStmt.Cond.(*ast.UnaryExpr); ok {
-					if call, ok := uExpr.X.(*ast.CallExpr); ok && uExpr.Op == token.NOT {
-						if MatchCallExpr(call, "testing", "Short") {
-							return
-						}
-					}
-				}
-			}
-		} else {
-			if ifStmt, ok := fn.Body.List[0].(*ast.IfStmt); ok {
-				if call, ok := ifStmt.Cond.(*ast.CallExpr); ok {
-					if MatchCallExpr(call, "testing", "Short") && len(ifStmt.Body.List) > 0 {
-						if exprStmt, ok := ifStmt.Body.List[0].(*ast.ExprStmt); ok {
-							if call, ok := exprStmt.X.(*ast.CallExpr); ok {
-								if MatchCallExpr(call, "t", "Skip") {
-									return
-								}
-							}
-						}
-					}
-				}
+	fn, isFn := aNode.(*ast.FuncDecl)
+	if !isFn || !isTestFunc(fn) {
+		return
+	}
+	if len(fn.Body.List) > 0 {
+		if ifStmt, ok := fn.Body.List[0].(*ast.IfStmt); ok {
+			if isShortSkip(ifStmt) || (len(fn.Body.List) == 1 && isNotShort(ifStmt)) {
+				return
 			}
 		}
-		lrp.AddItem(fs.Position(aNode.Pos()), lr.GetID(), "Missing either 'if testing.Short() { t.Skip() }' or 'if !testing.Short() {}'")
 	}
+	lrp.AddItem(fs.Position(aNode.Pos()), lr.GetID(), "Missing either 'if testing.Short() { t.Skip() }' or 'if !testing.Short() {}'")
+}
+
+// isNotShort returns true if ifStmt is an if !testing.Short() {} statement.
+func isNotShort(ifStmt *ast.IfStmt) bool {
+	if uExpr, ok := ifStmt.Cond.(*ast.UnaryExpr); ok && uExpr.Op == token.NOT {
+		if call, ok := uExpr.X.(*ast.CallExpr); ok {
+			return MatchCallExpr(call, "testing", "Short")
+		}
+	}
+	return false
+}
+
+// isShortSkip returns true if ifStmt is an if testing.Short() { t.Skip() } statement.
+func isShortSkip(ifStmt *ast.IfStmt) bool {
+	call, ok := ifStmt.Cond.(*ast.CallExpr)
+	if !ok || !MatchCallExpr(call, "testing", "Short") || len(ifStmt.Body.List) == 0 {
+		return false
+	}
+	if exprStmt, ok := ifStmt.Body.List[0].(*ast.ExprStmt); ok {
+		if call, ok := exprStmt.X.(*ast.CallExpr); ok {
+			return MatchCallExpr(call, "t", "Skip")
+		}
+	}
+	return false
 }
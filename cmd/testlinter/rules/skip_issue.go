@@ -15,8 +15,12 @@
 package rules
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
 
 	"istio.io/tools/pkg/checker"
 )
@@ -25,16 +29,59 @@ import (
 // This helps to keep tracking of the issue that causes a test to be skipped.
 // For example, this is a valid call,
 // t.Skip("https://github.com/istio/istio/issues/6012")
-// t.SkipNow() and t.Skipf() are not allowed.
+// t.Skipf() is valid if its format string contains the url, and t.SkipNow() is not allowed.
 type SkipIssue struct {
 	skipArgsRegex string // Defines arg in t.Skip() that should match.
 }
 
+// DefaultIssuePattern is the regular expression of the issue urls accepted by NewSkipByIssue.
+const DefaultIssuePattern = `https:\/\/github\.com\/istio\/istio\/issues\/[0-9]+`
+
+// skipIssueMessage is the message of the findings of SkipIssue.
+const skipIssueMessage = "Only t.Skip() and t.Skipf() are allowed and they should contain an url to an issue."
+
 // NewSkipByIssue creates and returns a SkipIssue object.
 func NewSkipByIssue() *SkipIssue {
 	return &SkipIssue{
-		skipArgsRegex: `https:\/\/github\.com\/istio\/istio\/issues\/[0-9]+`,
+		skipArgsRegex: DefaultIssuePattern,
+	}
+}
+
+// NewSkipByIssuePatterns creates and returns a SkipIssue object which accepts the issue urls
+// matching any of the given regular expressions.
+func NewSkipByIssuePatterns(patterns []string) (*SkipIssue, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no issue url pattern")
+	}
+	alternatives := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("invalid issue url pattern %q: %v", p, err)
+		}
+		alternatives = append(alternatives, "(?:"+p+")")
 	}
+	return &SkipIssue{skipArgsRegex: strings.Join(alternatives, "|")}, nil
+}
+
+// IssueTrackerPattern returns the regular expression of the issue urls of a named tracker, which
+// is one of:
+// github:<owner>/<repo> for the GitHub issues of a repository,
+// github:<owner> for the GitHub issues of all the repositories of an owner,
+// jira:<url> for the tickets of the Jira instance at url, such as https://issues.example.com/browse/PROJ-123.
+func IssueTrackerPattern(tracker string) (string, error) {
+	kind, name := tracker, ""
+	if i := strings.IndexByte(tracker, ':'); i >= 0 {
+		kind, name = tracker[:i], strings.TrimSuffix(tracker[i+1:], "/")
+	}
+	switch {
+	case kind == "github" && name != "" && strings.Count(name, "/") == 0:
+		return `https://github\.com/` + regexp.QuoteMeta(name) + `/[\w.-]+/issues/[0-9]+`, nil
+	case kind == "github" && name != "" && strings.Count(name, "/") == 1:
+		return `https://github\.com/` + regexp.QuoteMeta(name) + `/issues/[0-9]+`, nil
+	case kind == "jira" && name != "":
+		return regexp.QuoteMeta(name) + `/browse/[A-Z][A-Z0-9_]*-[0-9]+`, nil
+	}
+	return "", fmt.Errorf("invalid issue tracker %q, expected github:<owner>[/<repo>] or jira:<url>", tracker)
 }
 
 // GetID returns skip_by_issue_rule.
@@ -42,23 +89,89 @@ func (lr *SkipIssue) GetID() string {
 	return GetCallerFileName()
 }
 
-// Check returns verifies if aNode is a valid t.Skip(), or aNode is not t.Skip(), t.SkipNow(),
-// and t.Skipf(). If verification fails lrp creates a new report.
-// This is an example for valid call t.Skip("https://github.com/istio/istio/issues/6012")
+// GetSeverity returns error.
+func (lr *SkipIssue) GetSeverity() checker.Severity {
+	return checker.SeverityError
+}
+
+// GetConfig returns the regular expression of the accepted issue urls.
+func (lr *SkipIssue) GetConfig() string {
+	return lr.skipArgsRegex
+}
+
+// Check returns verifies if aNode is a valid t.Skip() or t.Skipf(), or aNode is not t.Skip(),
+// t.SkipNow(), and t.Skipf(). If verification fails lrp creates a new report.
+// Calls are checked anywhere in the file, including in nested blocks, subtests and helpers, on
+// any variable declared as a *testing.T, *testing.B or testing.TB, see isTestingReceiver. Calls
+// in the body of an if testing.Short() statement skip the test in short mode and are allowed.
+// These are examples of valid calls:
+// t.Skip("https://github.com/istio/istio/issues/6012"),
+// t.Skipf("flaky on %s: https://github.com/istio/istio/issues/6012", runtime.GOOS).
 // These calls are not valid:
 // t.Skip("https://istio.io/"),
 // t.SkipNow(),
 // t.Skipf("https://istio.io/%d", x).
 func (lr *SkipIssue) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
-	if fn, isFn := aNode.(*ast.FuncDecl); isFn {
-		for _, bd := range fn.Body.List {
-			if ok, _ := matchFunc(bd, "t", "SkipNow"); ok {
-				lrp.AddItem(fs.Position(bd.Pos()), lr.GetID(), "Only t.Skip() is allowed and t.Skip() should contain an url to GitHub issue.")
-			} else if ok, _ := matchFunc(bd, "t", "Skipf"); ok {
-				lrp.AddItem(fs.Position(bd.Pos()), lr.GetID(), "Only t.Skip() is allowed and t.Skip() should contain an url to GitHub issue.")
-			} else if ok, fcall := matchFunc(bd, "t", "Skip"); ok && !matchFuncArgs(fcall, lr.skipArgsRegex) {
-				lrp.AddItem(fs.Position(bd.Pos()), lr.GetID(), "Only t.Skip() is allowed and t.Skip() should contain an url to GitHub issue.")
+	if file, ok := aNode.(*ast.File); ok {
+		lr.checkFile(file, fs, lrp, func(sel *ast.SelectorExpr) bool {
+			return isTestingReceiver(sel.X)
+		}, func(call *ast.CallExpr) bool {
+			return MatchCallExpr(call, "testing", "Short")
+		})
+	}
+}
+
+// CheckTyped verifies aNode like Check, but recognises skip calls by resolving them to methods of
+// the testing package, so that the receiver can be any expression, such as s.T() in a suite.
+func (lr *SkipIssue) CheckTyped(aNode ast.Node, fs *token.FileSet, info *types.Info, _ *types.Package, lrp *checker.Report) {
+	if file, ok := aNode.(*ast.File); ok {
+		lr.checkFile(file, fs, lrp, func(sel *ast.SelectorExpr) bool {
+			isTesting, resolved := isTestingMethod(info, sel)
+			return isTesting || (!resolved && isTestingReceiver(sel.X))
+		}, func(call *ast.CallExpr) bool {
+			return checker.MatchCallFunc(info, call, "testing", "Short")
+		})
+	}
+}
+
+// TypesOptional returns true, as Check recognises the skip calls on testing receivers without
+// type information. Types are only loaded for the rule with -types.
+func (lr *SkipIssue) TypesOptional() bool {
+	return true
+}
+
+// checkFile checks the skip calls of file, which are the calls of a method selected by sel for
+// which isTesting returns true. Calls in the body of an if statement whose condition is a call
+// for which isShort returns true are not checked, as they skip the test in short mode as
+// required by ShortSkip.
+func (lr *SkipIssue) checkFile(file *ast.File, fs *token.FileSet, lrp *checker.Report,
+	isTesting func(sel *ast.SelectorExpr) bool, isShort func(call *ast.CallExpr) bool) {
+	shortSkips := shortSkipCalls(file, isShort)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && !shortSkips[call] {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && isTesting(sel) {
+				lr.checkSkip(call, sel.Sel, fs, lrp)
 			}
 		}
+		return true
+	})
+}
+
+// checkSkip reports fcall if it is a call to the method name of a testing receiver which skips
+// the test without an url to an issue.
+func (lr *SkipIssue) checkSkip(fcall *ast.CallExpr, name *ast.Ident, fs *token.FileSet, lrp *checker.Report) {
+	switch name.Name {
+	case "SkipNow":
+		// Suggest the t.Skip() call, the issue url still has to be filled in.
+		fix := checker.NewTextEdit(fs, name.Pos(), fcall.End(), `Skip("TODO: add url to issue")`)
+		lrp.AddItemWithFix(fs.Position(fcall.Pos()), lr.GetID(), skipIssueMessage, fix)
+	case "Skipf":
+		if !matchFirstArg(fcall, lr.skipArgsRegex) {
+			lrp.AddItem(fs.Position(fcall.Pos()), lr.GetID(), skipIssueMessage)
+		}
+	case "Skip":
+		if !matchFuncArgs(fcall, lr.skipArgsRegex) {
+			lrp.AddItem(fs.Position(fcall.Pos()), lr.GetID(), skipIssueMessage)
+		}
 	}
 }
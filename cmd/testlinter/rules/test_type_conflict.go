@@ -0,0 +1,85 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// TestTypeConflict requires that the sources of the test type of a test file agree: its
+// // +testtype=<type> directive, its build constraints and its path, see GetTestType. For example,
+// a file in an e2e directory with a // +build integ constraint is reported. The sources are
+// compared in the order of ClassificationPrecedence, and sources left out of it are ignored. It
+// also reports directives with an unknown test type.
+type TestTypeConflict struct{}
+
+// NewTestTypeConflict creates and returns a TestTypeConflict object.
+func NewTestTypeConflict() *TestTypeConflict {
+	return &TestTypeConflict{}
+}
+
+// GetID returns test_type_conflict.
+func (lr *TestTypeConflict) GetID() string {
+	return GetCallerFileName()
+}
+
+// GetSeverity returns error.
+func (lr *TestTypeConflict) GetSeverity() checker.Severity {
+	return checker.SeverityError
+}
+
+// GetConfig returns the classification precedence the sources are compared in.
+func (lr *TestTypeConflict) GetConfig() string {
+	return strings.Join(ClassificationPrecedence, ",")
+}
+
+// Check verifies that the sources of the test type of aNode agree, if it is a file. If
+// verification fails lrp creates a new report.
+func (lr *TestTypeConflict) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	file, ok := aNode.(*ast.File)
+	if !ok {
+		return
+	}
+	pos := fs.Position(file.Package)
+	sources, err := testTypeSources(pos.Filename, fileHeader(file))
+	if err != nil {
+		lrp.AddItem(pos, lr.GetID(), err.Error())
+	}
+	var first string
+	for _, source := range ClassificationPrecedence {
+		t, ok := sources[source]
+		if !ok {
+			continue
+		}
+		if first == "" {
+			first = source
+		} else if t != sources[first] {
+			lrp.AddItem(pos, lr.GetID(), fmt.Sprintf("Test type %s from the %s conflicts with test type %s from the %s.",
+				sources[first], sourceNames[first], t, sourceNames[source]))
+		}
+	}
+}
+
+// sourceNames describe the sources of test types in findings.
+var sourceNames = map[string]string{
+	DirectiveSource: "+testtype directive",
+	TagSource:       "build constraints",
+	PathSource:      "file path",
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duplicate
+
+import (
+	"testing"
+)
+
+type testCase struct {
+	name string
+	in   int
+}
+
+func TestDuplicate(t *testing.T) {
+	cases := []testCase{
+		{name: "one", in: 1},
+		{name: "two", in: 2},
+		{name: "one", in: 3}, // want `Test case name "one" is already used on line 28`
+		{name: "", in: 4},    // want `Test case has an empty name`
+		{in: 5},              // want `Test case has an empty name`
+		{},                   // want `Test case has an empty name`
+		{"six", 6},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.in)
+		})
+	}
+}
+
+func TestInline(t *testing.T) {
+	for _, tc := range []*testCase{
+		{name: "a"},
+		&testCase{name: "a"}, // want `Test case name "a" is already used on line 45`
+	} {
+		t.Run(tc.name, func(t *testing.T) {})
+	}
+}
+
+func TestOtherField(t *testing.T) {
+	var cases = []struct{ desc, name string }{
+		{desc: "same", name: "x"},
+		{desc: "same", name: "y"}, // want `Test case name "same" is already used on line 54`
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {})
+	}
+}
+
+func TestNoSubtest(t *testing.T) {
+	cases := []testCase{{name: "a"}, {name: "a"}}
+	for _, tc := range cases {
+		t.Log(tc.name)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external_test
+
+import (
+	"testing"
+)
+
+type suite struct{}
+
+func (suite) Run() {}
+
+// framework stands in for the Istio test framework package.
+var framework = struct {
+	NewSuite func(name string, m *testing.M) suite
+}{}
+
+func TestMain(m *testing.M) {
+	framework.NewSuite("external", m).Run()
+}
+
+func TestB(t *testing.T) {
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"testing"
+)
+
+func TestUsesHelpers(t *testing.T) {
+	check(t, 1)
+	t.Fatal("tests do not need t.Helper()")
+}
+
+func check(t *testing.T, n int) { // want `Helper check calls t.Fatalf\(\) but not t.Helper\(\)`
+	if n != 1 {
+		t.Fatalf("n is %d", n)
+	}
+}
+
+func checkWithHelper(t *testing.T, n int) {
+	t.Helper()
+	if n != 1 {
+		t.Errorf("n is %d", n)
+	}
+}
+
+func checkTB(tb testing.TB) { // want `Helper checkTB calls tb.Error\(\) but not tb.Helper\(\)`
+	tb.Error("failed")
+}
+
+func logOnly(t *testing.T) {
+	t.Log("no failure")
+}
+
+func subtests(t *testing.T) {
+	t.Run("sub", func(t *testing.T) {
+		t.Fatal("subtests report their own failures")
+	})
+}
+
+func BenchmarkFails(b *testing.B) {
+	b.Fatal("benchmarks do not need b.Helper()")
+}
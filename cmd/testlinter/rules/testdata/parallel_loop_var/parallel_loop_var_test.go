@@ -0,0 +1,82 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parallel
+
+import (
+	"testing"
+)
+
+func TestCaptured(t *testing.T) {
+	cases := []struct{ name, in string }{{"a", "x"}, {"b", "y"}}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if tc.in == "" { // want `Loop variable tc is captured by a parallel subtest`
+				t.Errorf("case %d", i) // want `Loop variable i is captured by a parallel subtest`
+			}
+			t.Log(tc.name)
+		})
+	}
+}
+
+func TestRebound(t *testing.T) {
+	for _, tc := range []string{"a", "b"} {
+		tc := tc
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+			t.Log(tc)
+		})
+	}
+}
+
+func TestSequential(t *testing.T) {
+	for _, tc := range []string{"a", "b"} {
+		t.Run(tc, func(t *testing.T) {
+			t.Log(tc)
+		})
+	}
+}
+
+func TestNested(t *testing.T) {
+	for _, group := range []string{"a", "b"} {
+		for _, tc := range []string{"c", "d"} {
+			tc := tc
+			t.Run(tc, func(st *testing.T) {
+				st.Parallel()
+				st.Log(group, tc) // want `Loop variable group is captured by a parallel subtest`
+			})
+		}
+	}
+}
+
+func TestReboundInSubtest(t *testing.T) {
+	for i, tc := range []string{"a", "b"} {
+		t.Run(tc, func(t *testing.T) {
+			tc, i := tc, i
+			t.Parallel()
+			t.Log(tc, i)
+		})
+	}
+}
+
+func TestReboundAfterParallel(t *testing.T) {
+	for _, tc := range []string{"a", "b"} {
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+			tc := tc // want `Loop variable tc is captured by a parallel subtest`
+			t.Log(tc)
+		})
+	}
+}
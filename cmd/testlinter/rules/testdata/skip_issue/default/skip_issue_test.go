@@ -0,0 +1,44 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skipissue
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSkipWithIssue(t *testing.T) {
+	t.Skip("https://github.com/istio/istio/issues/6012")
+}
+
+func TestSkipWithoutIssue(t *testing.T) {
+	t.Skip("https://istio.io/") // want `should contain an url to an issue`
+}
+
+func TestSkipNow(t *testing.T) {
+	t.SkipNow() // want `Only t.Skip\(\) and t.Skipf\(\) are allowed`
+}
+
+func TestSkipf(t *testing.T) {
+	t.Skipf("https://github.com/istio/istio/issues/%d", 6012) // want `Only t.Skip\(\) and t.Skipf\(\) are allowed`
+}
+
+func TestSkipfWithIssue(t *testing.T) {
+	t.Skipf("flaky on %s: https://github.com/istio/istio/issues/6012", runtime.GOOS)
+}
+
+func TestSkipOtherRepository(t *testing.T) {
+	t.Skip("https://github.com/istio/api/issues/1234") // want `should contain an url to an issue`
+}
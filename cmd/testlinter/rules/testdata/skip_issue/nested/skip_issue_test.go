@@ -0,0 +1,78 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skipissue
+
+import (
+	"testing"
+)
+
+type suite struct {
+	t *testing.T
+}
+
+func (s *suite) T() *testing.T {
+	return s.t
+}
+
+type player struct{}
+
+func (p *player) Skip(reason string) {}
+
+func TestNestedBlock(t *testing.T) {
+	if len(t.Name()) > 0 {
+		t.Skip("flaky") // want `should contain an url to an issue`
+	}
+}
+
+func TestSubtest(t *testing.T) {
+	t.Run("sub", func(st *testing.T) {
+		st.SkipNow() // want `should contain an url to an issue`
+	})
+}
+
+func TestTable(t *testing.T) {
+	for _, name := range []string{"a", "b"} {
+		t.Run(name, func(t *testing.T) {
+			t.Skip("https://github.com/istio/istio/issues/6012")
+		})
+	}
+}
+
+func TestSuite(t *testing.T) {
+	s := &suite{t: t}
+	s.T().Skip("flaky") // want `should contain an url to an issue`
+}
+
+func TestNotTesting(t *testing.T) {
+	p := &player{}
+	p.Skip("not a test")
+}
+
+func BenchmarkSkip(b *testing.B) {
+	b.Skipf("too slow") // want `should contain an url to an issue`
+}
+
+func skipHelper(tb testing.TB) {
+	tb.Skip("in a helper") // want `should contain an url to an issue`
+}
+
+func TestShortMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	if !testing.Short() {
+		t.Skip("flaky") // want `should contain an url to an issue`
+	}
+}
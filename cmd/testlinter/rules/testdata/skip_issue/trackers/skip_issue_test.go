@@ -0,0 +1,44 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skipissue
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSkipGitHubRepository(t *testing.T) {
+	t.Skip("https://github.com/istio/api/issues/1234")
+}
+
+func TestSkipGitHubOwner(t *testing.T) {
+	t.Skipf("flaky on %s: https://github.com/istio-ecosystem/admiral/issues/12", runtime.GOOS)
+}
+
+func TestSkipJira(t *testing.T) {
+	t.Skip("https://issues.example.com/browse/MESH-42")
+}
+
+func TestSkipPattern(t *testing.T) {
+	t.Skip("tracked in https://git.example.com/mesh/istio/-/issues/7")
+}
+
+func TestSkipOtherRepository(t *testing.T) {
+	t.Skip("https://github.com/istio/istio/issues/6012") // want `should contain an url to an issue`
+}
+
+func TestSkipJiraSearch(t *testing.T) {
+	t.Skip("https://issues.example.com/issues/?jql=project") // want `should contain an url to an issue`
+}
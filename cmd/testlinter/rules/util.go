@@ -17,10 +17,13 @@ package rules
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"log"
+	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -48,6 +51,35 @@ func MatchCallExpr(ce *ast.CallExpr, pn string, mn string) bool {
 	return false
 }
 
+// importNames returns the names file refers to the package imported from pkgPath by. Dot imports
+// are not included.
+func importNames(file *ast.File, pkgPath string) map[string]bool {
+	names := map[string]bool{}
+	for _, spec := range file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err != nil || p != pkgPath {
+			continue
+		}
+		if spec.Name == nil {
+			names[path.Base(pkgPath)] = true
+		} else if spec.Name.Name != "." && spec.Name.Name != "_" {
+			names[spec.Name.Name] = true
+		}
+	}
+	return names
+}
+
+// matchImportedCall returns true if ce calls the function mn of a package imported by one of
+// names, as returned by importNames.
+func matchImportedCall(ce *ast.CallExpr, names map[string]bool, mn string) bool {
+	if sel, ok := ce.Fun.(*ast.SelectorExpr); ok {
+		// Names declared in the file have an Obj, and shadow the import.
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Obj == nil && names[pkg.Name] {
+			return sel.Sel.Name == mn
+		}
+	}
+	return false
+}
+
 // matchFuncArgs returns true if args in fcall matches argsR. argsR is regex.
 func matchFuncArgs(fcall *ast.CallExpr, argsR string) bool {
 	if len(fcall.Args) == 1 && len(argsR) > 0 {
@@ -61,19 +93,89 @@ func matchFuncArgs(fcall *ast.CallExpr, argsR string) bool {
 	return false
 }
 
-// matchFunc returns true if bd matches packageName and methodName, and returns CallExpr
-// element in bd for args check.
-func matchFunc(bd ast.Stmt, packageName string, methodName string) (bool, *ast.CallExpr) {
-	if exprStmt, ok := bd.(*ast.ExprStmt); ok {
-		if call, ok := exprStmt.X.(*ast.CallExpr); ok {
-			if fun, ok := call.Fun.(*ast.SelectorExpr); ok {
-				if astid, ok := fun.X.(*ast.Ident); ok {
-					if astid.String() == packageName && fun.Sel.String() == methodName {
-						return true, call
+// matchFirstArg returns true if the first arg in fcall, such as the format string of a printf
+// like call, matches argsR. argsR is regex.
+func matchFirstArg(fcall *ast.CallExpr, argsR string) bool {
+	if len(fcall.Args) > 0 {
+		return matchFuncArgs(&ast.CallExpr{Args: fcall.Args[:1]}, argsR)
+	}
+	return false
+}
+
+// testingTypes are the types of the testing package whose values can skip a test or fail it.
+var testingTypes = map[string]bool{"T": true, "B": true, "TB": true}
+
+// isTestingReceiver returns true if x is a variable declared in the file as a *testing.T,
+// *testing.B or testing.TB. Variables whose declaration has no explicit type, such as the ones
+// assigned with :=, are recognised by the names t, b and tb, and calls such as s.T() by the name
+// of the method.
+func isTestingReceiver(x ast.Expr) bool {
+	switch x := x.(type) {
+	case *ast.Ident:
+		if x.Obj != nil {
+			var typ ast.Expr
+			switch decl := x.Obj.Decl.(type) {
+			case *ast.Field:
+				typ = decl.Type
+			case *ast.ValueSpec:
+				typ = decl.Type
+			}
+			if typ != nil {
+				return isTestingType(typ)
+			}
+		}
+		return x.Name == "t" || x.Name == "b" || x.Name == "tb"
+	case *ast.CallExpr:
+		if sel, ok := x.Fun.(*ast.SelectorExpr); ok && len(x.Args) == 0 {
+			return sel.Sel.Name == "T" || sel.Sel.Name == "B"
+		}
+	}
+	return false
+}
+
+// isTestingType returns true if typ is *testing.T, *testing.B or testing.TB.
+func isTestingType(typ ast.Expr) bool {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if sel, ok := typ.(*ast.SelectorExpr); ok {
+		if pkg, ok := sel.X.(*ast.Ident); ok {
+			return pkg.Name == "testing" && testingTypes[sel.Sel.Name]
+		}
+	}
+	return false
+}
+
+// isTestingMethod returns true if sel selects a method of a type of the testing package, such as
+// the Skip method of *testing.T, which is promoted from an unexported type, or of testing.TB. It
+// also returns whether sel could be resolved with info.
+func isTestingMethod(info *types.Info, sel *ast.SelectorExpr) (isTesting bool, resolved bool) {
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return false, false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	return ok && sig.Recv() != nil && fn.Pkg() != nil && fn.Pkg().Path() == "testing", true
+}
+
+// shortSkipCalls returns the calls in root which are statements of the body of an if statement
+// whose condition is a call for which isShort returns true, such as t.Skip() in
+// if testing.Short() { t.Skip("...") }.
+func shortSkipCalls(root ast.Node, isShort func(call *ast.CallExpr) bool) map[*ast.CallExpr]bool {
+	calls := map[*ast.CallExpr]bool{}
+	ast.Inspect(root, func(n ast.Node) bool {
+		if ifStmt, ok := n.(*ast.IfStmt); ok {
+			if call, ok := ifStmt.Cond.(*ast.CallExpr); ok && isShort(call) {
+				for _, stmt := range ifStmt.Body.List {
+					if exprStmt, ok := stmt.(*ast.ExprStmt); ok {
+						if call, ok := exprStmt.X.(*ast.CallExpr); ok {
+							calls[call] = true
+						}
 					}
 				}
 			}
 		}
-	}
-	return false, nil
+		return true
+	})
+	return calls
 }
@@ -32,18 +32,18 @@ func getAbsPath(path string) string {
 
 func clearLintRulesList() {
 	checker.IgnoreTestLinterData = false
-	delete(LintRulesList, UnitTest)
-	delete(LintRulesList, IntegTest)
-	delete(LintRulesList, E2eTest)
+	delete(rules.LintRulesList, rules.UnitTest)
+	delete(rules.LintRulesList, rules.IntegTest)
+	delete(rules.LintRulesList, rules.E2eTest)
 }
 
 func TestUnitTestSkipByIssueRule(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[UnitTest] = []checker.Rule{rules.NewSkipByIssue()}
+	rules.LintRulesList[rules.UnitTest] = []checker.Rule{rules.NewSkipByIssue()}
 
 	rpts, _ := getReport([]string{"testdata/"})
 	expectedRpts := []string{getAbsPath("testdata/unit_test.go") +
-		":24:2:Only t.Skip() is allowed and t.Skip() should contain an url to GitHub issue. (skip_issue)"}
+		":24:2:Only t.Skip() and t.Skipf() are allowed and they should contain an url to an issue. (skip_issue)"}
 
 	if !reflect.DeepEqual(rpts, expectedRpts) {
 		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
@@ -52,7 +52,7 @@ func TestUnitTestSkipByIssueRule(t *testing.T) {
 
 func TestUnitTestNoShortRule(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[UnitTest] = []checker.Rule{rules.NewNoShort()}
+	rules.LintRulesList[rules.UnitTest] = []checker.Rule{rules.NewNoShort()}
 
 	rpts, _ := getReport([]string{"testdata/"})
 	expectedRpts := []string{getAbsPath("testdata/unit_test.go") + ":48:5:testing.Short() is disallowed. (no_short)"}
@@ -64,10 +64,12 @@ func TestUnitTestNoShortRule(t *testing.T) {
 
 func TestUnitTestNoSleepRule(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[UnitTest] = []checker.Rule{rules.NewNoSleep()}
+	rules.LintRulesList[rules.UnitTest] = []checker.Rule{rules.NewNoSleep()}
 
 	rpts, _ := getReport([]string{"testdata/"})
-	expectedRpts := []string{getAbsPath("testdata/unit_test.go") + ":66:2:time.Sleep() is disallowed. (no_sleep)"}
+	expectedRpts := []string{
+		getAbsPath("testdata/aliased_test.go") + ":24:2:time.Sleep() is disallowed. (no_sleep)",
+		getAbsPath("testdata/unit_test.go") + ":66:2:time.Sleep() is disallowed. (no_sleep)"}
 
 	if !reflect.DeepEqual(rpts, expectedRpts) {
 		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
@@ -76,7 +78,7 @@ func TestUnitTestNoSleepRule(t *testing.T) {
 
 func TestUnitTestNoGoroutineRule(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[UnitTest] = []checker.Rule{rules.NewNoGoroutine()}
+	rules.LintRulesList[rules.UnitTest] = []checker.Rule{rules.NewNoGoroutine()}
 
 	rpts, _ := getReport([]string{"testdata/"})
 	expectedRpts := []string{getAbsPath("testdata/unit_test.go") + ":75:2:goroutine is disallowed. (no_goroutine)"}
@@ -88,7 +90,7 @@ func TestUnitTestNoGoroutineRule(t *testing.T) {
 
 func TestUnitTestWhitelist(t *testing.T) {
 	clearLintRulesList()
-	LintRulesList[UnitTest] = []checker.Rule{rules.NewSkipByIssue(),
+	rules.LintRulesList[rules.UnitTest] = []checker.Rule{rules.NewSkipByIssue(),
 		rules.NewNoShort(),
 		rules.NewNoSleep(),
 		rules.NewNoGoroutine()}
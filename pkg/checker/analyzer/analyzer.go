@@ -0,0 +1,140 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analyzer adapts checker rules to the go/analysis framework, so that they run under
+// go vet, golangci-lint and other analysis drivers, which take care of loading and caching
+// packages.
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+
+	"golang.org/x/tools/go/analysis"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// New returns an analyzer named after the ID of rule. It applies rule to the files of a package
+// for which factory returns a rule with the same ID, using the rule returned by factory. If doc
+// is empty, a generic description is used.
+//
+// Files are checked with the type information of the package, and //lint:ignore comments
+// naming the rule are honored. A PackageRule also checks the files of the package it applies
+// to as a whole. Malformed or unused suppression comments are only reported by the standalone
+// linters, since an analyzer sees a single rule.
+func New(rule checker.Rule, factory checker.RulesFactory, doc string) *analysis.Analyzer {
+	id := rule.GetID()
+	if doc == "" {
+		doc = fmt.Sprintf("report findings of the %s rule", id)
+	}
+	return &analysis.Analyzer{
+		Name: id,
+		Doc:  doc,
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return nil, run(pass, id, factory)
+		},
+	}
+}
+
+// NewAll returns an analyzer, as created by New, for each distinct rule ID among rules.
+func NewAll(factory checker.RulesFactory, rules ...checker.Rule) []*analysis.Analyzer {
+	seen := map[string]bool{}
+	var analyzers []*analysis.Analyzer
+	for _, rule := range rules {
+		if seen[rule.GetID()] {
+			continue
+		}
+		seen[rule.GetID()] = true
+		analyzers = append(analyzers, New(rule, factory, ""))
+	}
+	return analyzers
+}
+
+// run checks the files of the package of pass with the rule identified by id.
+func run(pass *analysis.Pass, id string, factory checker.RulesFactory) error {
+	var files []*ast.File
+	var rules [][]checker.Rule
+	for _, file := range pass.Files {
+		path := pass.Fset.Position(file.Pos()).Filename
+		info, err := os.Stat(path)
+		if err != nil {
+			// Files generated by the driver, such as the output of cgo, are not on disk.
+			continue
+		}
+		var fileRules []checker.Rule
+		for _, rule := range factory.GetRules(path, info) {
+			if rule.GetID() == id {
+				fileRules = append(fileRules, rule)
+			}
+		}
+		if len(fileRules) > 0 {
+			files = append(files, file)
+			rules = append(rules, fileRules)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	report := checker.NewLintReport()
+	err := checker.CheckPackageFiles(pass.Fset, files, rules, pass.TypesInfo, pass.Pkg, checker.NewWhitelist(nil), report)
+	if err != nil {
+		return err
+	}
+	tokenFiles := map[string]*token.File{}
+	for _, file := range files {
+		tf := pass.Fset.File(file.Pos())
+		tokenFiles[tf.Name()] = tf
+	}
+	for _, f := range report.Findings() {
+		if tf, ok := tokenFiles[f.Pos.Filename]; ok && f.RuleID == id {
+			pass.Report(diagnostic(tf, f))
+		}
+	}
+	return nil
+}
+
+// diagnostic converts a finding in the file tf to a diagnostic, with its edits as a suggested fix.
+func diagnostic(tf *token.File, f checker.Finding) analysis.Diagnostic {
+	d := analysis.Diagnostic{
+		Pos:      position(tf, f.Pos.Offset),
+		Category: f.RuleID,
+		Message:  f.Message,
+	}
+	if len(f.Edits) > 0 {
+		fix := analysis.SuggestedFix{Message: f.Message}
+		for _, e := range f.Edits {
+			fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{
+				Pos:     position(tf, e.Offset),
+				End:     position(tf, e.End),
+				NewText: []byte(e.NewText),
+			})
+		}
+		d.SuggestedFixes = []analysis.SuggestedFix{fix}
+	}
+	return d
+}
+
+// position returns the position of offset in tf, clamped to the file.
+func position(tf *token.File, offset int) token.Pos {
+	if offset < 0 {
+		offset = 0
+	} else if offset > tf.Size() {
+		offset = tf.Size()
+	}
+	return tf.Pos(offset)
+}
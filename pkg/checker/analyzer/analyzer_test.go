@@ -0,0 +1,132 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// noBad reports every call to a function named bad, with a fix calling good instead.
+type noBad struct{}
+
+func (lr *noBad) GetID() string {
+	return "no_bad"
+}
+
+func (lr *noBad) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	if ce, ok := aNode.(*ast.CallExpr); ok {
+		if id, ok := ce.Fun.(*ast.Ident); ok && id.Name == "bad" {
+			lrp.AddItemWithFix(fs.Position(ce.Pos()), lr.GetID(), "bad() is disallowed.",
+				checker.NewTextEdit(fs, id.Pos(), id.End(), "good"))
+		}
+	}
+}
+
+// goFiles applies the given rules to every go file.
+type goFiles []checker.Rule
+
+func (f goFiles) GetRules(absp string, info os.FileInfo) []checker.Rule {
+	if info.IsDir() || !strings.HasSuffix(absp, ".go") {
+		return nil
+	}
+	return f
+}
+
+// runAnalyzer runs a on the package made of the given files, and returns its diagnostics as
+// "line:column:message" strings, each followed by the edits of its fixes as "start-end:text"
+// strings of offsets.
+func runAnalyzer(t *testing.T, a *analysis.Analyzer, files ...string) []string {
+	fset := token.NewFileSet()
+	var syntax []*ast.File
+	for _, name := range files {
+		path, _ := filepath.Abs(name)
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		syntax = append(syntax, f)
+	}
+	info := &types.Info{Uses: map[*ast.Ident]types.Object{}}
+	pkg, err := (&types.Config{}).Check("testdata", fset, syntax, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	pass := &analysis.Pass{
+		Analyzer:  a,
+		Fset:      fset,
+		Files:     syntax,
+		Pkg:       pkg,
+		TypesInfo: info,
+		Report: func(d analysis.Diagnostic) {
+			pos := fset.Position(d.Pos)
+			got = append(got, fmt.Sprintf("%d:%d:%s", pos.Line, pos.Column, d.Message))
+			for _, fix := range d.SuggestedFixes {
+				for _, e := range fix.TextEdits {
+					got = append(got, fmt.Sprintf("%d-%d:%s", fset.Position(e.Pos).Offset, fset.Position(e.End).Offset, e.NewText))
+				}
+			}
+		},
+	}
+	if _, err := a.Run(pass); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestAnalyzer(t *testing.T) {
+	a := New(&noBad{}, goFiles{&noBad{}}, "")
+	if a.Name != "no_bad" {
+		t.Errorf("analyzer name is %q, expected no_bad", a.Name)
+	}
+
+	got := runAnalyzer(t, a, "testdata/bad.go")
+	expected := []string{
+		"21:2:bad() is disallowed.",
+		"681-684:good",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("diagnostics don't match\nReceived: %v\nExpected: %v", got, expected)
+	}
+}
+
+func TestAnalyzerFactory(t *testing.T) {
+	// The factory does not return the rule of the analyzer, so the file is not checked.
+	a := New(&noBad{}, goFiles{}, "")
+	if got := runAnalyzer(t, a, "testdata/bad.go"); len(got) != 0 {
+		t.Errorf("expected no diagnostics, received: %v", got)
+	}
+}
+
+func TestNewAll(t *testing.T) {
+	analyzers := NewAll(goFiles{}, &noBad{}, &noBad{})
+	if len(analyzers) != 1 {
+		t.Errorf("expected one analyzer per rule ID, received %d", len(analyzers))
+	}
+}
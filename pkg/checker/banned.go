@@ -0,0 +1,186 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+)
+
+// BannedAPI is an entry of the banned_apis section of the configuration file. It bans the use of
+// some package level functions, variables, constants or types of a package.
+type BannedAPI struct {
+	// ID is the rule ID of the findings, used to whitelist and suppress them.
+	ID string `json:"id"`
+	// Package is the import path of the package.
+	Package string `json:"package"`
+	// Symbols are the names of the banned package members. All members are banned if it is empty
+	// or contains "*".
+	Symbols []string `json:"symbols,omitempty"`
+	// Message is added to the findings, typically to point at the replacement.
+	Message string `json:"message,omitempty"`
+	// Paths are globs of the files the ban applies to, all files if empty.
+	Paths []string `json:"paths,omitempty"`
+	// Allowed are globs of the files in which the API is allowed even if they match Paths.
+	Allowed []string `json:"allowed,omitempty"`
+	// Severity is the default severity of the findings, error if empty.
+	Severity Severity `json:"severity,omitempty"`
+}
+
+// BannedAPIRule is the Rule reporting uses of a BannedAPI.
+//
+// With type information, uses are resolved through the package, so renamed and dot imports are
+// reported as well. Without it, which is the default, a use is a selector on a name the package
+// is imported as.
+type BannedAPIRule struct {
+	api BannedAPI
+}
+
+// NewBannedAPIRule creates and returns a BannedAPIRule for api.
+func NewBannedAPIRule(api BannedAPI) *BannedAPIRule {
+	return &BannedAPIRule{api: api}
+}
+
+// GetID returns the ID of the banned API entry.
+func (r *BannedAPIRule) GetID() string {
+	return r.api.ID
+}
+
+// GetSeverity returns the severity of the banned API entry.
+func (r *BannedAPIRule) GetSeverity() Severity {
+	if r.api.Severity == "" {
+		return SeverityError
+	}
+	return r.api.Severity
+}
+
+// GetConfig returns the banned API entry as JSON.
+func (r *BannedAPIRule) GetConfig() string {
+	data, _ := json.Marshal(r.api)
+	return string(data)
+}
+
+// TypesOptional returns true, as Check finds the qualified uses of the API without type
+// information. Types are only loaded for the rule when requested, see Options.LoadTypes.
+func (r *BannedAPIRule) TypesOptional() bool {
+	return true
+}
+
+// Check reports the uses of the banned API in the file, when given the *ast.File node.
+func (r *BannedAPIRule) Check(aNode ast.Node, fs *token.FileSet, lrp *Report) {
+	file, ok := aNode.(*ast.File)
+	if !ok || !r.applies(fs, file) {
+		return
+	}
+	names := map[string]bool{}
+	for _, spec := range file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == r.api.Package {
+			if spec.Name != nil {
+				names[spec.Name.Name] = true
+			} else {
+				names[path.Base(p)] = true
+			}
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			// Names declared in the file have an Obj, and shadow the import.
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil && names[x.Name] && r.banned(sel.Sel.Name) {
+				r.report(fs, sel.Pos(), sel.Sel.Name, lrp)
+			}
+		}
+		return true
+	})
+}
+
+// CheckTyped reports the uses of the banned API in the file, when given the *ast.File node.
+func (r *BannedAPIRule) CheckTyped(aNode ast.Node, fs *token.FileSet, info *types.Info, _ *types.Package, lrp *Report) {
+	file, ok := aNode.(*ast.File)
+	if !ok || !r.applies(fs, file) {
+		return
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			// Report qualified uses at the start of the selector, like the untyped check.
+			if r.bannedObject(info.Uses[n.Sel]) {
+				r.report(fs, n.Pos(), n.Sel.Name, lrp)
+				return false
+			}
+		case *ast.Ident:
+			// Uses through dot imports.
+			if r.bannedObject(info.Uses[n]) {
+				r.report(fs, n.Pos(), n.Name, lrp)
+			}
+		}
+		return true
+	})
+}
+
+// applies returns true if the ban applies to file.
+func (r *BannedAPIRule) applies(fs *token.FileSet, file *ast.File) bool {
+	filename := fs.Position(file.Pos()).Filename
+	return (len(r.api.Paths) == 0 || matchAny(r.api.Paths, filename)) && !matchAny(r.api.Allowed, filename)
+}
+
+// banned returns true if the package member name is banned.
+func (r *BannedAPIRule) banned(name string) bool {
+	if len(r.api.Symbols) == 0 {
+		return true
+	}
+	for _, s := range r.api.Symbols {
+		if s == "*" || s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// bannedObject returns true if obj is a banned package member.
+func (r *BannedAPIRule) bannedObject(obj types.Object) bool {
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != r.api.Package {
+		return false
+	}
+	if _, ok := obj.(*types.PkgName); ok || obj.Parent() != obj.Pkg().Scope() {
+		return false
+	}
+	return r.banned(obj.Name())
+}
+
+func (r *BannedAPIRule) report(fs *token.FileSet, pos token.Pos, name string, lrp *Report) {
+	msg := fmt.Sprintf("%s.%s is disallowed", path.Base(r.api.Package), name)
+	if r.api.Message != "" {
+		msg += ", " + r.api.Message
+	}
+	lrp.AddItem(fs.Position(pos), r.GetID(), msg)
+}
+
+// matchAny returns true if path matches any of the globs.
+func matchAny(globs []string, path string) bool {
+	for _, g := range globs {
+		if matched, _ := MatchGlob(g, path); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newBannedTestRules() []Rule {
+	return []Rule{
+		NewBannedAPIRule(BannedAPI{ID: "no_ioutil", Package: "io/ioutil", Message: "please use os instead"}),
+		NewBannedAPIRule(BannedAPI{ID: "no_log_fatal", Package: "log", Symbols: []string{"Fatal", "Fatalf"}}),
+		NewBannedAPIRule(BannedAPI{ID: "no_title", Package: "strings", Symbols: []string{"Title"}}),
+	}
+}
+
+func TestBannedAPIs(t *testing.T) {
+	report := NewLintReport()
+	err := CheckWithOptions([]string{"testdata/banned"}, goFiles(newBannedTestRules()), NewWhitelist(nil), report,
+		Options{LoadTypes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := getAbsPath("testdata/banned/banned.go")
+	expectedRpts := []string{
+		file + ":24:9:ioutil.ReadFile is disallowed, please use os instead (no_ioutil)",
+		file + ":25:2:log.Fatal is disallowed (no_log_fatal)",
+		file + ":27:6:strings.Title is disallowed (no_title)",
+	}
+	if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+}
+
+func TestBannedAPIsUntyped(t *testing.T) {
+	report := NewLintReport()
+	if err := Check([]string{"testdata/banned"}, goFiles(newBannedTestRules()), NewWhitelist(nil), report); err != nil {
+		t.Fatal(err)
+	}
+
+	// Uses through dot imports are only found with type information.
+	file := getAbsPath("testdata/banned/banned.go")
+	expectedRpts := []string{
+		file + ":24:9:ioutil.ReadFile is disallowed, please use os instead (no_ioutil)",
+		file + ":25:2:log.Fatal is disallowed (no_log_fatal)",
+	}
+	if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+}
+
+func TestBannedAPIScope(t *testing.T) {
+	file := getAbsPath("testdata/banned/banned.go")
+	for _, tc := range []struct {
+		paths, allowed []string
+		reported       bool
+	}{
+		{reported: true},
+		{paths: []string{"**/banned/*.go"}, reported: true},
+		{paths: []string{"**/other/*.go"}, reported: false},
+		{paths: []string{"**/banned/*.go"}, allowed: []string{file}, reported: false},
+	} {
+		rule := NewBannedAPIRule(BannedAPI{ID: "no_ioutil", Package: "io/ioutil", Paths: tc.paths, Allowed: tc.allowed})
+		report := NewLintReport()
+		if err := Check([]string{file}, goFiles{rule}, NewWhitelist(nil), report); err != nil {
+			t.Fatal(err)
+		}
+		if reported := len(report.Findings()) > 0; reported != tc.reported {
+			t.Errorf("paths %v, allowed %v: reported = %v, expected %v", tc.paths, tc.allowed, reported, tc.reported)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Baseline is a set of existing findings that are accepted, so that new rules can be enforced on
+// new code only. Findings are identified by rule, file and the source line they are on, rather
+// than by line number, so that the baseline survives unrelated edits to the file.
+type Baseline struct {
+	Entries []BaselineEntry `json:"entries"`
+
+	// dir is the directory file paths are relative to.
+	dir string
+}
+
+// BaselineEntry is a group of identical findings in the baseline.
+type BaselineEntry struct {
+	Rule string `json:"rule"`
+	// File is the slash separated path of the file, relative to the baseline file.
+	File string `json:"file"`
+	// Snippet is the source line of the findings, with whitespace normalized.
+	Snippet string `json:"snippet"`
+	// Count is the number of findings with the same rule, file and snippet.
+	Count int `json:"count"`
+}
+
+type baselineKey struct {
+	rule, file, snippet string
+}
+
+func (e BaselineEntry) key() baselineKey {
+	return baselineKey{rule: e.Rule, file: e.File, snippet: e.Snippet}
+}
+
+// NewBaseline returns a baseline accepting all the findings in report, for a baseline file
+// stored at path.
+func NewBaseline(path string, report *Report) *Baseline {
+	b := &Baseline{dir: baselineDir(path)}
+	counts := b.count(report)
+	for k, n := range counts {
+		b.Entries = append(b.Entries, BaselineEntry{Rule: k.rule, File: k.file, Snippet: k.snippet, Count: n})
+	}
+	b.sort()
+	return b
+}
+
+// LoadBaseline reads the baseline file at path.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read baseline file %s: %v", path, err)
+	}
+	b := &Baseline{dir: baselineDir(path)}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("unable to parse baseline file %s: %v", path, err)
+	}
+	return b, nil
+}
+
+// Write writes the baseline to the file at path. File paths are rewritten if the file is stored
+// in a different directory than the baseline was loaded from.
+func (b *Baseline) Write(path string) error {
+	if dir := baselineDir(path); dir != b.dir {
+		for i, e := range b.Entries {
+			if rel, err := filepath.Rel(dir, filepath.Join(b.dir, filepath.FromSlash(e.File))); err == nil {
+				b.Entries[i].File = filepath.ToSlash(rel)
+			}
+		}
+		b.dir = dir
+		b.sort()
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Filter removes the findings accepted by the baseline from report. When the baseline has n
+// identical findings, only the first n of them are removed.
+func (b *Baseline) Filter(report *Report) {
+	remaining := b.counts()
+	snippets := newSnippetCache()
+	report.Filter(func(f Finding) bool {
+		k, ok := b.key(f, snippets)
+		if !ok || remaining[k] == 0 {
+			return true
+		}
+		remaining[k]--
+		return false
+	})
+}
+
+// Fixed returns the baseline entries, or the part of their count, that no longer have a matching
+// finding in report.
+func (b *Baseline) Fixed(report *Report) []BaselineEntry {
+	current := b.count(report)
+	var fixed []BaselineEntry
+	for _, e := range b.Entries {
+		if n := e.Count - current[e.key()]; n > 0 {
+			e.Count = n
+			fixed = append(fixed, e)
+		}
+	}
+	return fixed
+}
+
+// Prune removes the fixed findings, as returned by Fixed, from the baseline.
+func (b *Baseline) Prune(report *Report) {
+	current := b.count(report)
+	entries := b.Entries[:0]
+	for _, e := range b.Entries {
+		if n := current[e.key()]; n > 0 {
+			if n < e.Count {
+				e.Count = n
+			}
+			entries = append(entries, e)
+		}
+	}
+	b.Entries = entries
+}
+
+func (b *Baseline) counts() map[baselineKey]int {
+	counts := map[baselineKey]int{}
+	for _, e := range b.Entries {
+		counts[e.key()] += e.Count
+	}
+	return counts
+}
+
+// count groups the findings of report by baseline key.
+func (b *Baseline) count(report *Report) map[baselineKey]int {
+	counts := map[baselineKey]int{}
+	snippets := newSnippetCache()
+	for _, f := range report.Findings() {
+		if k, ok := b.key(f, snippets); ok {
+			counts[k]++
+		}
+	}
+	return counts
+}
+
+// key returns the baseline key of f. Findings which are not tied to a file have no key.
+func (b *Baseline) key(f Finding, snippets *snippetCache) (baselineKey, bool) {
+	if f.Pos.Filename == "" || f.RuleID == "" {
+		return baselineKey{}, false
+	}
+	file := f.Pos.Filename
+	if rel, err := filepath.Rel(b.dir, file); err == nil {
+		file = rel
+	}
+	return baselineKey{
+		rule:    f.RuleID,
+		file:    filepath.ToSlash(file),
+		snippet: snippets.line(f.Pos.Filename, f.Pos.Line),
+	}, true
+}
+
+func (b *Baseline) sort() {
+	sort.Slice(b.Entries, func(i, j int) bool {
+		x, y := b.Entries[i], b.Entries[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Rule != y.Rule {
+			return x.Rule < y.Rule
+		}
+		return x.Snippet < y.Snippet
+	})
+}
+
+func baselineDir(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.Dir(path)
+}
+
+// snippetCache reads the normalized source lines of files, reading each file once.
+type snippetCache struct {
+	files map[string][]string
+}
+
+func newSnippetCache() *snippetCache {
+	return &snippetCache{files: map[string][]string{}}
+}
+
+// line returns the given 1-based line of file with its whitespace normalized, or an empty string
+// if the file cannot be read.
+func (c *snippetCache) line(file string, line int) string {
+	lines, ok := c.files[file]
+	if !ok {
+		if data, err := ioutil.ReadFile(file); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		c.files[file] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.Join(strings.Fields(lines[line-1]), " ")
+}
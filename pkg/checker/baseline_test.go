@@ -0,0 +1,101 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const baselineSource = `package baseline
+
+func bad(int) {}
+
+func f() {
+	bad(1)
+	bad(2)
+	bad(2)
+}
+`
+
+// The baselined findings moved down, bad(1) is fixed and a third bad(2) is new.
+const changedBaselineSource = `package baseline
+
+func bad(int) {}
+
+func g() {}
+
+func f() {
+	bad(2)
+	bad(2)
+	bad(2)
+}
+`
+
+func TestBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checker-baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "baseline.go")
+	baselinePath := filepath.Join(dir, "baseline.json")
+
+	check := func(src string) *Report {
+		if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		report := NewLintReport()
+		if err := Check([]string{path}, goFiles{&noBad{}}, NewWhitelist(nil), report); err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+
+	if err := NewBaseline(baselinePath, check(baselineSource)).Write(baselinePath); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadBaseline(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedEntries := []BaselineEntry{
+		{Rule: "no_bad", File: "baseline.go", Snippet: "bad(1)", Count: 1},
+		{Rule: "no_bad", File: "baseline.go", Snippet: "bad(2)", Count: 2},
+	}
+	if !reflect.DeepEqual(baseline.Entries, expectedEntries) {
+		t.Fatalf("baseline entries don't match\nReceived: %v\nExpected: %v", baseline.Entries, expectedEntries)
+	}
+
+	report := check(changedBaselineSource)
+	fixed := baseline.Fixed(report)
+	if expectedFixed := expectedEntries[:1]; !reflect.DeepEqual(fixed, expectedFixed) {
+		t.Errorf("fixed entries don't match\nReceived: %v\nExpected: %v", fixed, expectedFixed)
+	}
+
+	baseline.Filter(report)
+	expectedRpts := []string{path + ":10:2:bad() is disallowed. (no_bad)"}
+	if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+
+	baseline.Prune(check(changedBaselineSource))
+	if expectedPruned := expectedEntries[1:]; !reflect.DeepEqual(baseline.Entries, expectedPruned) {
+		t.Errorf("pruned entries don't match\nReceived: %v\nExpected: %v", baseline.Entries, expectedPruned)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bufio"
+	"go/build"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// generatedHeader matches the comment marking generated files, see https://golang.org/s/generatedcode.
+var generatedHeader = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// buildContext returns the build context files are matched against.
+func (o Options) buildContext() build.Context {
+	ctx := build.Default
+	if o.GOOS != "" {
+		ctx.GOOS = o.GOOS
+	}
+	if o.GOARCH != "" {
+		ctx.GOARCH = o.GOARCH
+	}
+	ctx.BuildTags = o.Tags
+	return ctx
+}
+
+// skipFile returns true if the go file at path is excluded by its build constraints, both without
+// and with the optional tags, or is generated and generated files are not included.
+func (o Options) skipFile(ctx *build.Context, path string) (bool, error) {
+	if !strings.HasSuffix(path, ".go") {
+		return false, nil
+	}
+	match, err := ctx.MatchFile(filepath.Dir(path), filepath.Base(path))
+	if err == nil && !match && len(o.OptionalTags) > 0 {
+		optional := *ctx
+		optional.BuildTags = append(append([]string{}, o.Tags...), o.OptionalTags...)
+		match, err = optional.MatchFile(filepath.Dir(path), filepath.Base(path))
+	}
+	if err != nil || !match {
+		return true, err
+	}
+	if o.IncludeGenerated {
+		return false, nil
+	}
+	return isGenerated(path)
+}
+
+// isGenerated returns true if the go file at path has a generated code header before its package
+// clause.
+func isGenerated(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if generatedHeader.MatchString(line) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,239 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Cache stores the findings of files on disk, so that unchanged files are not parsed again. An
+// entry is keyed by the path and content of the file, the rules which apply to it and their
+// settings, see ConfigRule, and the version of the linter.
+//
+// The findings of files checked with type information or by a PackageRule depend on the other
+// files of their package, so the key of these files also covers the content of the Go files of
+// their directory and the build configuration. The packages they import are not part of the key.
+type Cache struct {
+	dir     string
+	version string
+
+	hits, misses int
+}
+
+// NewCache returns a cache stored in dir, for findings of the given linter version.
+func NewCache(dir string, version string) *Cache {
+	return &Cache{dir: dir, version: version}
+}
+
+// DefaultCacheDir returns the directory of the cache of the linter named tool, in the user cache
+// directory.
+func DefaultCacheDir(tool string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "istio-checker", tool), nil
+}
+
+// ExecutableVersion returns a hash of the running executable, which changes whenever the linter
+// or its rules are rebuilt.
+func ExecutableVersion() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Stats returns the number of files whose findings were found in the cache, and the number of
+// files which were checked and stored in the cache, since the cache was created.
+func (c *Cache) Stats() (hits, misses int) {
+	return c.hits, c.misses
+}
+
+// key returns the cache key of the file at path with content src, checked with the rules which
+// are not whitelisted for it. pkg identifies the content of the package of the file, and is empty
+// if the findings of the file only depend on the file.
+func (c *Cache) key(path string, src []byte, rules []Rule, whitelist *Whitelist, pkg string) string {
+	var active []string
+	for _, rule := range whitelist.Filter(path, rules) {
+		// The settings of a rule are part of the key, as they change its findings.
+		if cr, ok := rule.(ConfigRule); ok {
+			active = append(active, rule.GetID()+"="+cr.GetConfig())
+		} else {
+			active = append(active, rule.GetID())
+		}
+	}
+	sort.Strings(active)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", c.version, path, pkg)
+	for _, a := range active {
+		fmt.Fprintf(h, "%s\x00", a)
+	}
+	_, _ = h.Write(src)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// packageHash returns a hash of the names and content of the Go files in dir, and of the build
+// configuration of opts, or an empty string if they cannot be read.
+func packageHash(dir string, opts Options) string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%v\x00", opts.GOOS, opts.GOARCH, strings.Join(opts.Tags, ","),
+		strings.Join(opts.OptionalTags, ","), opts.LoadTypes)
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+			continue
+		}
+		src, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return ""
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", info.Name(), len(src))
+		_, _ = h.Write(src)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// serve adds the cached findings of jobs to report, and returns the jobs which still have to be
+// checked, with the key to store their findings under. The files of a PackageRule are only served
+// if all the files of their directory are, since the rule checks them together.
+func (c *Cache) serve(jobs []fileJob, whitelist *Whitelist, opts Options, report *Report) []fileJob {
+	packages := map[string]string{}
+	missedDirs := map[string]bool{}
+	cached := make([][]Finding, len(jobs))
+	hit := make([]bool, len(jobs))
+	for i := range jobs {
+		job := &jobs[i]
+		dir := filepath.Dir(job.path)
+		inPackage := needsTypes(job.rules, opts) || hasPackageRule(job.rules)
+		pkg := ""
+		if inPackage {
+			if _, ok := packages[dir]; !ok {
+				packages[dir] = packageHash(dir, opts)
+			}
+			pkg = packages[dir]
+		}
+		src, err := ioutil.ReadFile(job.path)
+		if err == nil && (!inPackage || pkg != "") {
+			job.key = c.key(job.path, src, job.rules, whitelist, pkg)
+			cached[i], hit[i] = c.get(job.key)
+		}
+		// Files which are not served are checked, which reports the errors reading them.
+		if !hit[i] && hasPackageRule(job.rules) {
+			missedDirs[dir] = true
+		}
+	}
+
+	var unserved []fileJob
+	for i, job := range jobs {
+		if !hit[i] || (hasPackageRule(job.rules) && missedDirs[filepath.Dir(job.path)]) {
+			if job.key != "" {
+				c.misses++
+			}
+			unserved = append(unserved, job)
+			continue
+		}
+		c.hits++
+		for _, f := range cached[i] {
+			report.AddFinding(f)
+		}
+	}
+	return unserved
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the findings stored for key, if any.
+func (c *Cache) get(key string) ([]Finding, bool) {
+	data, err := ioutil.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil, false
+	}
+	var findings []Finding
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, false
+	}
+	return findings, true
+}
+
+// put stores findings for key. The cache is best effort, so errors are ignored.
+func (c *Cache) put(key string, findings []Finding) {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	data, err := json.Marshal(findings)
+	if err != nil {
+		return
+	}
+	path := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	// Write to a temporary file first, so that concurrent linters never read a partial entry.
+	tmp, err := ioutil.TempFile(filepath.Dir(path), key+".tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// finishCached adds the findings of the visited file to report like finish, and stores them in
+// cache under the key of the file, if it has one.
+func (fv *FileVisitor) finishCached(cache *Cache, report *Report) {
+	if cache == nil || fv.cacheKey == "" {
+		fv.finish(report)
+		return
+	}
+	fileReport := NewLintReport()
+	fv.finish(fileReport)
+	cache.put(fv.cacheKey, fileReport.Findings())
+	for _, f := range fileReport.Findings() {
+		report.AddFinding(f)
+	}
+}
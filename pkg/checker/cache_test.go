@@ -0,0 +1,131 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"go/ast"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingBad is noBad counting the files it visits.
+type countingBad struct {
+	noBad
+	files *int32
+}
+
+func (lr *countingBad) Check(aNode ast.Node, fs *token.FileSet, lrp *Report) {
+	if _, ok := aNode.(*ast.File); ok {
+		atomic.AddInt32(lr.files, 1)
+	}
+	lr.noBad.Check(aNode, fs, lrp)
+}
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checker-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src, err := ioutil.ReadFile("testdata/suppress/suppress.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "src", "suppress.go")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var files int32
+	check := func() []string {
+		report := NewLintReport()
+		opts := Options{Cache: NewCache(filepath.Join(dir, "cache"), "v1")}
+		if err := CheckWithOptions([]string{file}, goFiles{&countingBad{files: &files}}, NewWhitelist(nil), report, opts); err != nil {
+			t.Fatal(err)
+		}
+		return report.Items()
+	}
+
+	first := check()
+	if len(first) == 0 || files != 1 {
+		t.Fatalf("expected findings from checking the file once, received %v after %d checks", first, files)
+	}
+	if second := check(); !reflect.DeepEqual(second, first) || files != 1 {
+		t.Errorf("expected the cached findings without checking the file again, received %v after %d checks",
+			second, files)
+	}
+
+	if err := ioutil.WriteFile(file, append(src, []byte("\nfunc added() {\n\tbad()\n}\n")...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if third := check(); len(third) != len(first)+1 || files != 2 {
+		t.Errorf("expected the changed file to be checked again, received %v after %d checks", third, files)
+	}
+}
+
+func TestCachePackageRule(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checker-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	addFile := func(name string) {
+		if err := ioutil.WriteFile(filepath.Join(src, name), []byte("package p\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addFile("a.go")
+	addFile("b.go")
+
+	cache := NewCache(filepath.Join(dir, "cache"), "v1")
+	check := func() []string {
+		report := NewLintReport()
+		if err := CheckWithOptions([]string{src}, goFiles{&packageFiles{}}, NewWhitelist(nil), report, Options{Cache: cache}); err != nil {
+			t.Fatal(err)
+		}
+		return report.Items()
+	}
+
+	first := check()
+	if second := check(); !reflect.DeepEqual(second, first) {
+		t.Errorf("cached findings %v differ from %v", second, first)
+	}
+	if hits, misses := cache.Stats(); hits != 2 || misses != 2 {
+		t.Errorf("expected the package to be served from the cache, received %d hits and %d misses", hits, misses)
+	}
+
+	// A new file of the package changes the findings of the other files.
+	addFile("c.go")
+	third := check()
+	if len(third) != 3 || !strings.Contains(third[0], "3 files") || !strings.Contains(third[1], "3 files") {
+		t.Errorf("expected all the files of the package to be checked again, received %v", third)
+	}
+	if hits, misses := cache.Stats(); hits != 2 || misses != 5 {
+		t.Errorf("expected the package to be checked again, received %d hits and %d misses", hits, misses)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ChangedLines holds the lines added or modified by a change, by absolute file path.
+type ChangedLines map[string]map[int]bool
+
+// hunkHeader matches the header of a hunk in a unified diff, such as "@@ -1,2 +3,4 @@".
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff returns the lines added or modified by the unified diff read from r. File
+// names in the diff are relative to root, and may have the a/ and b/ prefixes used by git.
+func ParseUnifiedDiff(r io.Reader, root string) (ChangedLines, error) {
+	changed := ChangedLines{}
+	var lines map[int]bool
+	// Lines left in the current hunk, on the old and new side.
+	oldLeft, newLeft := 0, 0
+	line := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if lines != nil {
+					lines[line] = true
+				}
+				line++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, `\`):
+				// "\ No newline at end of file"
+			default:
+				line++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			name := strings.TrimPrefix(text, "+++ ")
+			if i := strings.IndexByte(name, '\t'); i >= 0 {
+				name = name[:i]
+			}
+			if name == "/dev/null" {
+				lines = nil
+				continue
+			}
+			name = strings.TrimPrefix(name, "b/")
+			if !filepath.IsAbs(name) {
+				name = filepath.Join(root, filepath.FromSlash(name))
+			}
+			lines = changed[name]
+			if lines == nil {
+				lines = map[int]bool{}
+				changed[name] = lines
+			}
+		case strings.HasPrefix(text, "@@"):
+			m := hunkHeader.FindStringSubmatch(text)
+			if m == nil {
+				return nil, fmt.Errorf("invalid hunk header %q", text)
+			}
+			oldLeft, newLeft = hunkCount(m[1]), hunkCount(m[3])
+			line, _ = strconv.Atoi(m[2])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// hunkCount parses the optional line count of a hunk range, which defaults to one.
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// GitChangedLines returns the lines added or modified in the working tree since the git revision
+// rev, as reported by git diff. All the lines of untracked files which are not ignored are
+// changed, as they are new.
+func GitChangedLines(rev string) (ChangedLines, error) {
+	root, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root = strings.TrimSpace(root)
+	diff, err := runGit("diff", "--no-color", "--no-ext-diff", "--unified=0", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	changed, err := ParseUnifiedDiff(strings.NewReader(diff), root)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := runGit("ls-files", "--others", "--exclude-standard", "--full-name", "-z", "--", root)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(untracked, "\x00") {
+		if name == "" {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(name))
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		lines := map[int]bool{}
+		for i := 1; i <= bytes.Count(src, []byte("\n"))+1; i++ {
+			lines[i] = true
+		}
+		changed[path] = lines
+	}
+	return changed, nil
+}
+
+func runGit(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %v: %s", strings.Join(args, " "), err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// Contains returns true if line of file was added or modified.
+func (c ChangedLines) Contains(file string, line int) bool {
+	if lines, ok := c[file]; ok {
+		return lines[line]
+	}
+	// The paths of the diff may have symbolic links resolved.
+	if resolved, err := filepath.EvalSymlinks(file); err == nil && resolved != file {
+		return c[resolved][line]
+	}
+	return false
+}
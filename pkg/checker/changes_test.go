@@ -0,0 +1,137 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDiff = `diff --git a/pkg/a.go b/pkg/a.go
+index 1111111..2222222 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -3,0 +4,2 @@ func a() {
++	b()
++++c
+@@ -10,3 +12,3 @@ func d() {
+ 	e()
+-	f()
++	g()
+ 	h()
+diff --git a/pkg/removed.go b/pkg/removed.go
+deleted file mode 100644
+--- a/pkg/removed.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package pkg
+diff --git a/pkg/new.go b/pkg/new.go
+new file mode 100644
+--- /dev/null
++++ b/pkg/new.go
+@@ -0,0 +1 @@
++package pkg
+`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	changed, err := ParseUnifiedDiff(strings.NewReader(testDiff), "/src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ChangedLines{
+		"/src/pkg/a.go":   {4: true, 5: true, 13: true},
+		"/src/pkg/new.go": {1: true},
+	}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Errorf("changed lines don't match\nReceived: %v\nExpected: %v", changed, expected)
+	}
+}
+
+func TestFilterChanged(t *testing.T) {
+	report := NewLintReport()
+	report.AddItem(token.Position{Filename: "/src/pkg/a.go", Line: 4, Column: 2}, "rule", "new")
+	report.AddItem(token.Position{Filename: "/src/pkg/a.go", Line: 12, Column: 2}, "rule", "old")
+	report.AddItem(token.Position{Filename: "/src/pkg/b.go", Line: 1, Column: 1}, "rule", "untouched")
+	report.AddString("no position")
+
+	report.FilterChanged(ChangedLines{"/src/pkg/a.go": {4: true, 13: true}})
+	expectedRpts := []string{"no position", "/src/pkg/a.go:4:2:new (rule)"}
+	if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+}
+
+func TestGitChangedLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checker-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	write := func(name, content string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git := func(args ...string) {
+		if _, err := runGit(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	write("tracked.go", "package p\n")
+	write(".gitignore", "ignored.go\n")
+	git("add", ".")
+	git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial")
+	write("tracked.go", "package p\n\nfunc f() {}\n")
+	write("untracked.go", "package p\n\nfunc g() {}\n")
+	write("ignored.go", "package p\n")
+
+	changed, err := GitChangedLines("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		file    string
+		line    int
+		changed bool
+	}{
+		{"tracked.go", 1, false},
+		{"tracked.go", 3, true},
+		{"untracked.go", 1, true},
+		{"untracked.go", 3, true},
+		{"ignored.go", 1, false},
+	} {
+		if got := changed.Contains(filepath.Join(dir, tc.file), tc.line); got != tc.changed {
+			t.Errorf("%s:%d changed %v, expected %v", tc.file, tc.line, got, tc.changed)
+		}
+	}
+}
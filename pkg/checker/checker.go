@@ -19,23 +19,130 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strings"
+	"regexp"
+	"runtime"
+	"sync"
 )
 
 var (
-	// IgnoreTestLinterData skips over unit tests
+	// IgnoreTestLinterData skips over the test data of the linters in this repository, unless the
+	// data is the path being checked.
 	IgnoreTestLinterData = true
 )
 
+// linterTestData matches the test data directories of the linters and of their rules.
+var linterTestData = regexp.MustCompile(`(^|/)(testlinter|envvarlinter)(/rules)?/testdata(/|$)`)
+
+// Options controls how Check walks and lints files.
+type Options struct {
+	// Workers is the number of files parsed and visited concurrently. Values below one
+	// default to the number of CPUs.
+	Workers int
+	// IncludeGenerated also checks the files with a "// Code generated ... DO NOT EDIT." header,
+	// which are skipped by default.
+	IncludeGenerated bool
+	// GOOS, GOARCH and Tags are the build configuration files are checked for. Files excluded by
+	// their build constraints or file name are skipped. GOOS and GOARCH default to the ones of
+	// go/build.Default.
+	GOOS   string
+	GOARCH string
+	Tags   []string
+	// OptionalTags are build tags which files may require on top of Tags without being skipped,
+	// such as the tags marking the test files of a type. Files whose constraints are satisfied
+	// either without or with them are checked.
+	OptionalTags []string
+	// LoadTypes loads type information for the files of an OptionalTypedRule, which are otherwise
+	// checked without it unless another rule needs it.
+	LoadTypes bool
+	// Cache, if not nil, stores the findings of files so that unchanged files are not checked
+	// again.
+	Cache *Cache
+}
+
+// fileJob is a file selected by the RulesFactory, together with the rules that apply to it.
+type fileJob struct {
+	path  string
+	rules []Rule
+	// key is the key the findings of the file are stored under in the cache, empty if they are not
+	// stored.
+	key string
+}
+
 // Check checks the list of files, and write to the given Report.
 func Check(paths []string, factory RulesFactory, whitelist *Whitelist, report *Report) error {
+	return CheckWithOptions(paths, factory, whitelist, report, Options{})
+}
+
+// CheckWithOptions checks the list of files using a pool of opts.Workers goroutines, and
+// write to the given Report.
+func CheckWithOptions(paths []string, factory RulesFactory, whitelist *Whitelist, report *Report, opts Options) error {
+	jobs, err := collectFiles(paths, factory, opts)
+	if err != nil {
+		return err
+	}
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if opts.Cache != nil {
+		jobs = opts.Cache.serve(jobs, whitelist, opts, report)
+	}
+	typed := loadTypedFiles(jobs, opts)
+
+	// Files with a PackageRule are kept until their package is checked, so that suppressions
+	// also apply to the findings of the package.
+	var mu sync.Mutex
+	var pending []*FileVisitor
+	queue := make(chan fileJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				v := fileCheck(job.path, job.rules, typed[job.path], whitelist, report)
+				if v == nil {
+					continue
+				}
+				v.cacheKey = job.key
+				if hasPackageRule(job.rules) {
+					mu.Lock()
+					pending = append(pending, v)
+					mu.Unlock()
+				} else {
+					v.finishCached(opts.Cache, report)
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+
+	checkPackages(pending, whitelist, report)
+	for _, v := range pending {
+		v.finishCached(opts.Cache, report)
+	}
+	return nil
+}
+
+// collectFiles walks the given paths and returns the files for which factory returns rules, and
+// which are not skipped by opts.
+func collectFiles(paths []string, factory RulesFactory, opts Options) ([]fileJob, error) {
 	// Empty paths means current dir.
 	if len(paths) == 0 {
 		paths = []string{"."}
 	}
 
+	ctx := opts.buildContext()
+	var jobs []fileJob
 	for _, path := range paths {
 		if !filepath.IsAbs(path) {
 			path, _ = filepath.Abs(path)
@@ -44,50 +151,150 @@ func Check(paths []string, factory RulesFactory, whitelist *Whitelist, report *R
 			if err != nil {
 				return fmt.Errorf("pervent panic by handling failure accessing a path %q: %v", fpath, err)
 			}
+			// TODO: skip over linter tests in a principled manner for all linters
+			if IgnoreTestLinterData && isLinterTestData(fpath) && !isLinterTestData(path) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
 			rules := factory.GetRules(fpath, info)
-			if len(rules) > 0 {
-				fileCheck(fpath, rules, whitelist, report)
+			if len(rules) == 0 {
+				return nil
+			}
+			skip, err := opts.skipFile(&ctx, fpath)
+			if err != nil {
+				return fmt.Errorf("unable to read %q: %v", fpath, err)
+			}
+			if !skip {
+				jobs = append(jobs, fileJob{path: fpath, rules: rules})
 			}
 			return nil
 		})
 		if err != nil {
-			return fmt.Errorf("error visiting the path %q: %v", path, err)
+			return nil, fmt.Errorf("error visiting the path %q: %v", path, err)
 		}
 	}
-	return nil
+	return jobs, nil
 }
 
-// fileCheck checks a file using the given rules, and write to the given Report.
-func fileCheck(path string, rules []Rule, whitelist *Whitelist, report *Report) {
-	// TODO: skip over linter tests in a principled manner for all linters
-	if IgnoreTestLinterData && strings.Contains(path, "testlinter/testdata") {
-		return
-	}
+// isLinterTestData returns true if path is in the test data of a linter.
+func isLinterTestData(path string) bool {
+	return linterTestData.MatchString(filepath.ToSlash(path))
+}
 
+// fileCheck checks a file using the given rules, and returns the visitor holding its findings, or
+// nil if the file cannot be parsed. If tf is not nil, it holds the file parsed and type checked as
+// part of its package.
+func fileCheck(path string, rules []Rule, tf *typedFile, whitelist *Whitelist, report *Report) *FileVisitor {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		report.AddString(fmt.Sprintf("%v", err))
+		return nil
+	}
+	if tf != nil {
+		return visitFile(path, src, tf.fset, tf.file, tf.info, tf.pkg, rules, whitelist)
+	}
 	fs := token.NewFileSet()
-	astFile, err := parser.ParseFile(fs, path, nil, parser.Mode(0))
+	astFile, err := parser.ParseFile(fs, path, src, parser.ParseComments)
 	if err != nil {
 		report.AddString(fmt.Sprintf("%v", err))
-		return
+		return nil
 	}
-	v := FileVisitor{
-		path:      path,
-		rules:     rules,
-		whitelist: whitelist,
-		fileset:   fs,
-		report:    report,
+	return visitFile(path, src, fs, astFile, nil, nil, rules, whitelist)
+}
+
+// CheckFile checks a file which was already parsed, with comments, using the given rules, and
+// write to the given Report. info and pkg hold the type information of the package of the file,
+// and may be nil. This lets other drivers, such as go/analysis, run the rules on their own ASTs.
+func CheckFile(fs *token.FileSet, file *ast.File, info *types.Info, pkg *types.Package, rules []Rule,
+	whitelist *Whitelist, report *Report) error {
+	path := fs.Position(file.Pos()).Filename
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	visitFile(path, src, fs, file, info, pkg, rules, whitelist).finish(report)
+	return nil
+}
+
+// CheckPackageFiles checks the parsed files of a package like CheckFile, where rules[i] are the
+// rules of files[i], and runs the PackageRules on the files of the package they apply to.
+func CheckPackageFiles(fs *token.FileSet, files []*ast.File, rules [][]Rule, info *types.Info, pkg *types.Package,
+	whitelist *Whitelist, report *Report) error {
+	visited := make([]*FileVisitor, 0, len(files))
+	for i, file := range files {
+		path := fs.Position(file.Pos()).Filename
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		visited = append(visited, visitFile(path, src, fs, file, info, pkg, rules[i], whitelist))
+	}
+	checkPackages(visited, whitelist, report)
+	for _, v := range visited {
+		v.finish(report)
+	}
+	return nil
+}
+
+// visitFile applies rules to the syntax tree of the file at path, whose source is src. The
+// findings are kept in the returned visitor until finish is called.
+func visitFile(path string, src []byte, fs *token.FileSet, astFile *ast.File, info *types.Info, pkg *types.Package,
+	rules []Rule, whitelist *Whitelist) *FileVisitor {
+	v := &FileVisitor{
+		path:         path,
+		file:         astFile,
+		rules:        rules,
+		checked:      whitelist.Filter(path, rules),
+		suppressions: parseSuppressions(fs, astFile, src, rules),
+		fileset:      fs,
+		info:         info,
+		pkg:          pkg,
+		report:       NewLintReport(),
 	}
 	// Walk through the files
-	ast.Walk(&v, astFile)
+	ast.Walk(v, astFile)
+	return v
+}
+
+// finish adds the findings of the visited file which are not suppressed to report.
+func (fv *FileVisitor) finish(report *Report) {
+	setDefaultSeverities(fv.rules, fv.report)
+	applySuppressions(fv.suppressions, fv.report.Findings(), report)
+}
+
+// setDefaultSeverities sets the severity of the findings of the SeverityRules among rules.
+func setDefaultSeverities(rules []Rule, report *Report) {
+	severities := map[string]Severity{}
+	for _, rule := range rules {
+		if sr, ok := rule.(SeverityRule); ok {
+			severities[rule.GetID()] = sr.GetSeverity()
+		}
+	}
+	if len(severities) == 0 {
+		return
+	}
+	report.setSeverities(func(f Finding) Severity {
+		if s, ok := severities[f.RuleID]; ok {
+			return s
+		}
+		return f.Severity
+	})
 }
 
 // FileVisitor visits the go file syntax tree and applies the given rules.
 type FileVisitor struct {
-	path      string
-	rules     []Rule     // rules to check
-	whitelist *Whitelist // rules to skip
-	fileset   *token.FileSet
-	report    *Report // report for linting process
+	path         string
+	file         *ast.File
+	rules        []Rule         // rules of the file
+	checked      []Rule         // rules of the file which are not whitelisted for it
+	suppressions []*suppression // lint:ignore comments in the file
+	fileset      *token.FileSet
+	info         *types.Info    // type information, nil if the package was not loaded
+	pkg          *types.Package // type checked package, nil if the package was not loaded
+	report       *Report        // report for the file, before suppressions are applied
+	cacheKey     string         // key of the findings of the file in the cache, empty if not cached
 }
 
 // Visit checks each node and runs the applicable checks.
@@ -97,8 +304,10 @@ func (fv *FileVisitor) Visit(node ast.Node) ast.Visitor {
 	}
 
 	// ApplyRules applies rules to node and generate lint report.
-	for _, rule := range fv.rules {
-		if !fv.whitelist.Apply(fv.path, rule) {
+	for _, rule := range fv.checked {
+		if tr, ok := rule.(TypedRule); ok && fv.info != nil {
+			tr.CheckTyped(node, fv.fileset, fv.info, fv.pkg, fv.report)
+		} else {
 			rule.Check(node, fv.fileset, fv.report)
 		}
 	}
@@ -0,0 +1,175 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// noBad reports every call to a function named bad.
+type noBad struct{}
+
+func (lr *noBad) GetID() string {
+	return "no_bad"
+}
+
+func (lr *noBad) Check(aNode ast.Node, fs *token.FileSet, lrp *Report) {
+	if ce, ok := aNode.(*ast.CallExpr); ok {
+		if id, ok := ce.Fun.(*ast.Ident); ok && id.Name == "bad" {
+			lrp.AddItem(fs.Position(ce.Pos()), lr.GetID(), "bad() is disallowed.")
+		}
+	}
+}
+
+// goFiles applies the given rules to every go file.
+type goFiles []Rule
+
+func (f goFiles) GetRules(absp string, info os.FileInfo) []Rule {
+	if info.IsDir() || !strings.HasSuffix(absp, ".go") {
+		return nil
+	}
+	return f
+}
+
+func getAbsPath(path string) string {
+	if !filepath.IsAbs(path) {
+		path, _ = filepath.Abs(path)
+	}
+	return path
+}
+
+func TestSuppressions(t *testing.T) {
+	report := NewLintReport()
+	if err := Check([]string{"testdata/suppress"}, goFiles{&noBad{}}, NewWhitelist(nil), report); err != nil {
+		t.Fatal(err)
+	}
+
+	file := getAbsPath("testdata/suppress/suppress.go")
+	expectedRpts := []string{
+		file + ":20:2:bad() is disallowed. (no_bad)",
+		file + ":25:2:bad() is disallowed. (no_bad)",
+		file + ":31:2:bad() is disallowed. (no_bad)",
+		file + ":41:2://lint:ignore directive for no_bad must give a reason (lint_ignore)",
+		file + ":46:2://lint:ignore directive for no_bad does not match any finding (lint_ignore)",
+		file + ":51:2:bad() is disallowed. (no_bad)",
+	}
+	if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+}
+
+func TestFileSuppression(t *testing.T) {
+	report := NewLintReport()
+	if err := Check([]string{"testdata/fileignore"}, goFiles{&noBad{}}, NewWhitelist(nil), report); err != nil {
+		t.Fatal(err)
+	}
+	if rpts := report.Items(); len(rpts) != 0 {
+		t.Errorf("expected no lint reports, received: %v", rpts)
+	}
+}
+
+// warnBad is noBad with findings that are warnings.
+type warnBad struct {
+	noBad
+}
+
+func (lr *warnBad) GetSeverity() Severity {
+	return SeverityWarning
+}
+
+func TestDefaultSeverity(t *testing.T) {
+	report := NewLintReport()
+	if err := Check([]string{"testdata/suppress"}, goFiles{&warnBad{}}, NewWhitelist(nil), report); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range report.Findings() {
+		expected := SeverityWarning
+		if f.RuleID == SuppressionRuleID {
+			expected = SeverityError
+		}
+		if f.Severity != expected {
+			t.Errorf("%v has severity %s, expected %s", f, f.Severity, expected)
+		}
+	}
+	if !report.HasFindingsAtLeast(SeverityError) {
+		t.Error("expected the lint_ignore findings to be errors")
+	}
+}
+
+func TestGeneratedFiles(t *testing.T) {
+	file := getAbsPath("testdata/generated/generated.go")
+	for _, include := range []bool{false, true} {
+		report := NewLintReport()
+		err := CheckWithOptions([]string{"testdata/generated"}, goFiles{&noBad{}}, NewWhitelist(nil), report,
+			Options{IncludeGenerated: include})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedRpts := []string{}
+		if include {
+			expectedRpts = []string{file + ":8:2:bad() is disallowed. (no_bad)"}
+		}
+		if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+			t.Errorf("IncludeGenerated %v: lint reports don't match\nReceived: %v\nExpected: %v", include, rpts, expectedRpts)
+		}
+	}
+}
+
+func TestBuildConstraints(t *testing.T) {
+	dir := getAbsPath("testdata/constraints")
+	for _, tc := range []struct {
+		opts  Options
+		files []string
+	}{
+		{Options{GOOS: "windows"}, []string{"plain.go", "untagged.go"}},
+		{Options{GOOS: "linux"}, []string{"linuxonly.go", "plain.go", "untagged.go"}},
+		{Options{GOOS: "windows", Tags: []string{"lintme"}}, []string{"plain.go", "tagged.go"}},
+		{Options{GOOS: "windows", OptionalTags: []string{"lintme"}}, []string{"plain.go", "tagged.go", "untagged.go"}},
+	} {
+		report := NewLintReport()
+		if err := CheckWithOptions([]string{dir}, goFiles{&noBad{}}, NewWhitelist(nil), report, tc.opts); err != nil {
+			t.Fatal(err)
+		}
+		var files []string
+		for _, f := range report.Findings() {
+			files = append(files, filepath.Base(f.Pos.Filename))
+		}
+		if !reflect.DeepEqual(files, tc.files) {
+			t.Errorf("%+v: checked files don't match\nReceived: %v\nExpected: %v", tc.opts, files, tc.files)
+		}
+	}
+}
+
+func TestIsLinterTestData(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"/src/tools/cmd/testlinter/testdata/unit_test.go":                  true,
+		"/src/tools/cmd/testlinter/rules/testdata":                         true,
+		"/src/tools/cmd/testlinter/rules/testdata/skip_issue/skip_test.go": true,
+		"/src/tools/cmd/envvarlinter/rules/testdata/no_os_env/env.go":      true,
+		"/src/tools/cmd/testlinter/rules/rules_test.go":                    false,
+		"/src/tools/pkg/checker/testdata/banned/banned.go":                 false,
+		"/src/tools/cmd/mytestlinter/testdata/unit_test.go":                false,
+	} {
+		if got := isLinterTestData(path); got != expected {
+			t.Errorf("isLinterTestData(%q) = %v, expected %v", path, got, expected)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkertest runs checker rules over testdata packages and compares their findings with
+// the expectations written in the source, in the style of analysistest.
+//
+// An expectation is a comment of the form
+//
+//	// want "regexp" `regexp`...
+//
+// Each regexp must match the message of a distinct finding on the line of the comment. Findings
+// without a matching expectation, and expectations without a matching finding, are test errors.
+package checkertest
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/scanner"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// Testing is the part of *testing.T used by Run.
+type Testing interface {
+	Errorf(format string, args ...interface{})
+}
+
+// TestData returns the absolute path of the testdata directory of the package being tested.
+func TestData() string {
+	dir, err := filepath.Abs("testdata")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+// Run checks the go files of each directory dir/pattern with rule, and reports to t the findings
+// and expectations which do not match. It returns the report of the findings, for further checks
+// such as of the suggested fixes.
+func Run(t Testing, dir string, rule checker.Rule, patterns ...string) *checker.Report {
+	return RunWithOptions(t, dir, rule, checker.Options{}, patterns...)
+}
+
+// RunWithOptions is like Run, but checks the files with opts, such as to check files for other
+// build tags.
+func RunWithOptions(t Testing, dir string, rule checker.Rule, opts checker.Options, patterns ...string) *checker.Report {
+	paths := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		path, err := filepath.Abs(filepath.Join(dir, p))
+		if err != nil {
+			t.Errorf("%v", err)
+			return checker.NewLintReport()
+		}
+		paths = append(paths, path)
+	}
+
+	report := checker.NewLintReport()
+	if err := checker.CheckWithOptions(paths, allGoFiles{rule}, checker.NewWhitelist(nil), report, opts); err != nil {
+		t.Errorf("unable to check %v: %v", paths, err)
+		return report
+	}
+
+	want := map[key][]*regexp.Regexp{}
+	for _, path := range paths {
+		if err := readExpectations(path, want); err != nil {
+			t.Errorf("%v", err)
+			return report
+		}
+	}
+
+	for _, f := range report.Findings() {
+		if f.Pos.Filename == "" {
+			t.Errorf("unexpected message: %s", f.Message)
+			continue
+		}
+		k := key{file: f.Pos.Filename, line: f.Pos.Line}
+		matched := false
+		for i, re := range want[k] {
+			if re.MatchString(f.Message) {
+				want[k] = append(want[k][:i], want[k][i+1:]...)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			t.Errorf("%v: unexpected finding: %s (%s)", f.Pos, f.Message, f.RuleID)
+		}
+	}
+
+	var missing []string
+	for k, res := range want {
+		for _, re := range res {
+			missing = append(missing, fmt.Sprintf("%s:%d: no finding was reported matching %q", k.file, k.line, re))
+		}
+	}
+	sort.Strings(missing)
+	for _, m := range missing {
+		t.Errorf("%s", m)
+	}
+	return report
+}
+
+// allGoFiles applies its rules to every go file.
+type allGoFiles []checker.Rule
+
+func (f allGoFiles) GetRules(absp string, info os.FileInfo) []checker.Rule {
+	if info.IsDir() || !strings.HasSuffix(absp, ".go") {
+		return nil
+	}
+	return f
+}
+
+// key is a line of a file.
+type key struct {
+	file string
+	line int
+}
+
+// readExpectations adds the expectations of the go files under path to want.
+func readExpectations(path string, want map[key][]*regexp.Regexp) error {
+	return filepath.Walk(path, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(fpath, ".go") {
+			return err
+		}
+		fs := token.NewFileSet()
+		file, err := parser.ParseFile(fs, fpath, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				if err := parseWant(fs, c, want); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// parseWant adds the expectations of c to want, if it is a want comment.
+func parseWant(fs *token.FileSet, c *ast.Comment, want map[key][]*regexp.Regexp) error {
+	text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+	if !strings.HasPrefix(text, "want ") {
+		return nil
+	}
+	pos := fs.Position(c.Pos())
+	k := key{file: pos.Filename, line: pos.Line}
+
+	var s scanner.Scanner
+	s.Init(strings.NewReader(strings.TrimPrefix(text, "want ")))
+	s.Mode = scanner.ScanStrings | scanner.ScanRawStrings
+	s.Error = func(*scanner.Scanner, string) {}
+	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
+		if tok != scanner.String && tok != scanner.RawString {
+			return fmt.Errorf("%v: want comment must only have quoted regexps, got %q", pos, s.TokenText())
+		}
+		pattern, err := strconv.Unquote(s.TokenText())
+		if err != nil {
+			return fmt.Errorf("%v: invalid quoted regexp %s: %v", pos, s.TokenText(), err)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%v: %v", pos, err)
+		}
+		want[k] = append(want[k], re)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkertest
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"istio.io/tools/pkg/checker"
+)
+
+// noBad reports every call to a function named bad.
+type noBad struct{}
+
+func (lr *noBad) GetID() string {
+	return "no_bad"
+}
+
+func (lr *noBad) Check(aNode ast.Node, fs *token.FileSet, lrp *checker.Report) {
+	if ce, ok := aNode.(*ast.CallExpr); ok {
+		if id, ok := ce.Fun.(*ast.Ident); ok && id.Name == "bad" {
+			lrp.AddItem(fs.Position(ce.Pos()), lr.GetID(), "bad() is disallowed.")
+		}
+	}
+}
+
+// recorder records the errors reported by Run.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRun(t *testing.T) {
+	r := &recorder{}
+	report := Run(r, TestData(), &noBad{}, "a")
+
+	file := filepath.Join(TestData(), "a", "a.go")
+	expected := []string{
+		file + ":25:2: unexpected finding: bad() is disallowed. (no_bad)",
+		file + `:21: no finding was reported matching "disallowed"`,
+		file + `:28: no finding was reported matching "bad"`,
+	}
+	if !reflect.DeepEqual(r.errors, expected) {
+		t.Errorf("errors don't match\nReceived: %q\nExpected: %q", r.errors, expected)
+	}
+	if n := len(report.Findings()); n != 3 {
+		t.Errorf("expected 3 findings in the report, received %d", n)
+	}
+}
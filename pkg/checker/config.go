@@ -0,0 +1,210 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config is the YAML configuration file of checker based linters. Linters can read their own
+// settings from other top level keys of the same file, see Decode.
+type Config struct {
+	// Whitelist lists the rules excluded from some files.
+	Whitelist []WhitelistEntry `json:"whitelist"`
+	// Severities overrides the default severity of the findings of some rules.
+	Severities []SeverityOverride `json:"severities"`
+	// BannedAPIs lists the APIs reported by BannedAPIRules.
+	BannedAPIs []BannedAPI `json:"banned_apis"`
+	// Rules maps kinds of files, as defined by the linter, to the IDs of the rules applied to them.
+	// It replaces the default rules of the linter for these kinds, see Registry.NewRules.
+	Rules map[string][]string `json:"rules"`
+
+	path string // path of the file, empty if the config was not loaded from a file
+	data []byte // content of the file
+}
+
+// SeverityOverride sets the severity of the findings of some rules, in some files.
+type SeverityOverride struct {
+	// Rules are the IDs of the rules, or "*" for all rules.
+	Rules []string `json:"rules"`
+	// Paths are globs of the files the override applies to, all files if empty.
+	Paths    []string `json:"paths,omitempty"`
+	Severity Severity `json:"severity"`
+}
+
+// LoadConfig reads the configuration file at path. Relative paths in the file are resolved
+// against the directory of the file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration file %s: %v", path, err)
+	}
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("unable to parse configuration file %s: %v", path, err)
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	dir := filepath.Dir(path)
+	lines := listItemLines(b, "whitelist")
+	for i := range c.Whitelist {
+		entry := &c.Whitelist[i]
+		entry.source = token.Position{Filename: path}
+		if i < len(lines) {
+			entry.source.Line, entry.source.Column = lines[i], 1
+		}
+		if len(entry.Paths) == 0 || len(entry.Rules) == 0 {
+			return nil, fmt.Errorf("whitelist entry %d in %s must have paths and rules", i, path)
+		}
+		resolvePaths(dir, entry.Paths)
+		if entry.Expires != "" {
+			if entry.expires, err = time.Parse("2006-01-02", entry.Expires); err != nil {
+				return nil, fmt.Errorf("whitelist entry %d in %s has invalid expiry date %q, expected YYYY-MM-DD",
+					i, path, entry.Expires)
+			}
+		}
+	}
+	for i := range c.Severities {
+		o := &c.Severities[i]
+		if len(o.Rules) == 0 {
+			return nil, fmt.Errorf("severities entry %d in %s must have rules", i, path)
+		}
+		if _, err := ParseSeverity(string(o.Severity)); err != nil {
+			return nil, fmt.Errorf("severities entry %d in %s: %v", i, path, err)
+		}
+		resolvePaths(dir, o.Paths)
+	}
+	ids := map[string]bool{}
+	for i := range c.BannedAPIs {
+		api := &c.BannedAPIs[i]
+		if api.ID == "" || api.Package == "" {
+			return nil, fmt.Errorf("banned_apis entry %d in %s must have an id and a package", i, path)
+		}
+		if ids[api.ID] {
+			return nil, fmt.Errorf("banned_apis entry %d in %s has duplicate id %q", i, path, api.ID)
+		}
+		ids[api.ID] = true
+		if api.Severity != "" {
+			if _, err := ParseSeverity(string(api.Severity)); err != nil {
+				return nil, fmt.Errorf("banned_apis entry %d in %s: %v", i, path, err)
+			}
+		}
+		resolvePaths(dir, api.Paths)
+		resolvePaths(dir, api.Allowed)
+	}
+	c.path, c.data = path, b
+	return &c, nil
+}
+
+// Decode reads the top level sections of the configuration file declared by the JSON tags of
+// settings, such as the settings of the rules of a linter, into settings. It leaves settings
+// unchanged if the config was not loaded from a file.
+func (c *Config) Decode(settings interface{}) error {
+	if c.data == nil {
+		return nil
+	}
+	if err := yaml.Unmarshal(c.data, settings); err != nil {
+		return fmt.Errorf("unable to parse configuration file %s: %v", c.path, err)
+	}
+	return nil
+}
+
+// BannedAPIRules returns a BannedAPIRule for each entry of the banned_apis section.
+func (c *Config) BannedAPIRules() []Rule {
+	rules := make([]Rule, 0, len(c.BannedAPIs))
+	for _, api := range c.BannedAPIs {
+		rules = append(rules, NewBannedAPIRule(api))
+	}
+	return rules
+}
+
+// resolvePaths makes the relative paths absolute, against dir. Paths starting with ** match in any
+// directory and are left as is.
+func resolvePaths(dir string, paths []string) {
+	for i, p := range paths {
+		if !filepath.IsAbs(p) && !strings.HasPrefix(p, "**") {
+			paths[i] = filepath.Join(dir, p)
+		}
+	}
+}
+
+// ApplySeverities sets the severity of the findings in report which match a severities entry of
+// the configuration. When several entries match a finding, the last one wins.
+func (c *Config) ApplySeverities(report *Report) {
+	if len(c.Severities) == 0 {
+		return
+	}
+	report.setSeverities(func(f Finding) Severity {
+		severity := f.Severity
+		for _, o := range c.Severities {
+			if o.matches(f) {
+				severity = o.Severity
+			}
+		}
+		return severity
+	})
+}
+
+// matches returns true if the override applies to f.
+func (o *SeverityOverride) matches(f Finding) bool {
+	ruleMatches := false
+	for _, id := range o.Rules {
+		ruleMatches = ruleMatches || id == "*" || id == f.RuleID
+	}
+	if !ruleMatches || f.RuleID == "" {
+		return false
+	}
+	return len(o.Paths) == 0 || matchAny(o.Paths, f.Pos.Filename)
+}
+
+// listItemLines returns the line numbers of the items of the block style list under the top level
+// key in the YAML document src. It is used to point findings about config entries to their line,
+// and returns fewer lines than items for lists in flow style.
+func listItemLines(src []byte, key string) []int {
+	var lines []int
+	inList := false
+	itemIndent := -1
+	for i, line := range strings.Split(string(src), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		if indent == 0 && !strings.HasPrefix(trimmed, "-") {
+			inList = strings.HasPrefix(trimmed, key+":")
+			itemIndent = -1
+			continue
+		}
+		if !inList || !strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		if itemIndent < 0 {
+			itemIndent = indent
+		}
+		if indent == itemIndent {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
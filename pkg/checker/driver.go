@@ -0,0 +1,369 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// fixedBaselineCommand is the command listing the baseline entries which no longer have a finding.
+const fixedBaselineCommand = "baseline-fixed"
+
+// Linter describes a command line linter run by Main.
+type Linter struct {
+	// Name is the name of the linter, used as the tool name of reports and for its cache directory.
+	Name string
+	// Rules returns the rules that apply to each file, once they are configured.
+	Rules RulesFactory
+	// Whitelist is the built in whitelist of the linter, from file path to rule IDs.
+	Whitelist map[string][]string
+	// Selectors describes the values of the -enable and -disable flags.
+	Selectors string
+	// Configure configures the rules from the config file at path. It is only called with -config.
+	Configure func(path string, config *Config) error
+	// SelectRules enables and disables rules as requested by the -enable and -disable flags.
+	SelectRules func(enable, disable string) error
+	// WriteRules lists the rules and where they are enabled, for -list-rules.
+	WriteRules func(w io.Writer) error
+	// OptionalTags are build tags files may require without being skipped, see Options.
+	OptionalTags []string
+	// Fixes adds the -fix and -diff flags, for linters with rules which suggest fixes.
+	Fixes bool
+	// Commands are the commands of the linter, besides linting and baseline-fixed.
+	Commands []Command
+}
+
+// Command is a command of a Linter, run when its name is the first argument.
+type Command struct {
+	Name string
+	// Formats describes the values of -format the command accepts.
+	Formats string
+	// Run runs the command on the given paths. It returns true if the command failed without
+	// an error, like linting with findings.
+	Run func(d *Driver, paths []string) (bool, error)
+}
+
+// Driver runs a Linter with the options set by its command line flags.
+type Driver struct {
+	linter Linter
+	flags  *flag.FlagSet
+	// config is the -config file, loaded once by configureRules.
+	config *Config
+
+	workers          *int
+	format           *string
+	configPath       *string
+	fix              *bool
+	diff             *bool
+	baselinePath     *string
+	writeBaseline    *string
+	newFromRev       *string
+	newFromPatch     *string
+	failOn           *string
+	includeGenerated *bool
+	goos             *string
+	goarch           *string
+	tags             *string
+	loadTypes        *bool
+	noCache          *bool
+	cacheDir         *string
+	listRules        *bool
+	enable           *string
+	disable          *string
+}
+
+// Main runs linter with the command line arguments, and exits.
+func Main(linter Linter) {
+	os.Exit(NewDriver(linter).Run(os.Args[1:]))
+}
+
+// NewDriver returns a Driver for linter, with its flags set to their defaults.
+func NewDriver(linter Linter) *Driver {
+	d := &Driver{linter: linter, flags: flag.NewFlagSet(linter.Name, flag.ExitOnError), config: &Config{}}
+	f := d.flags
+
+	formats := "Output format: text, json, sarif or checkstyle. Text is written to stderr, the others to stdout."
+	for _, c := range linter.Commands {
+		formats += fmt.Sprintf(" The %s command writes %s.", c.Name, c.Formats)
+	}
+	d.workers = f.Int("workers", runtime.NumCPU(), "Number of files to check concurrently.")
+	d.format = f.String("format", FormatText, formats)
+	d.configPath = f.String("config", "", "Path to a YAML configuration file.")
+	if linter.Fixes {
+		d.fix = f.Bool("fix", false, "Apply the suggested fixes to the files in place.")
+		d.diff = f.Bool("diff", false, "Print the suggested fixes as a unified diff instead of applying them.")
+	}
+	d.baselinePath = f.String("baseline", "", "Path to a baseline file of accepted findings, which are not reported.")
+	d.writeBaseline = f.String("write-baseline", "",
+		"Write all current findings to this baseline file instead of reporting them. With the "+
+			fixedBaselineCommand+" command, write the -baseline file without its fixed entries.")
+	d.newFromRev = f.String("new-from-rev", "", "Only report findings on lines changed since this git revision.")
+	d.newFromPatch = f.String("new-from-patch", "",
+		"Only report findings on lines added by this unified diff, '-' reads it from stdin. "+
+			"Paths in the diff are relative to the working directory.")
+	d.failOn = f.String("fail-on", string(SeverityError),
+		"Exit with a non-zero status only if there are findings of this severity or above: error, warning or info.")
+	d.includeGenerated = f.Bool("include-generated", false,
+		"Also check files with a '// Code generated ... DO NOT EDIT.' header.")
+	d.goos = f.String("goos", "", "Check files for this GOOS instead of the host one.")
+	d.goarch = f.String("goarch", "", "Check files for this GOARCH instead of the host one.")
+	d.tags = f.String("tags", "", "Comma separated list of build tags files are checked for.")
+	d.loadTypes = f.Bool("types", false,
+		"Load type information for the rules which can use it without needing it, to make them more precise.")
+	d.noCache = f.Bool("no-cache", false, "Check all files instead of reusing the findings of unchanged files.")
+	d.cacheDir = f.String("cache-dir", "",
+		"Directory of the cache of findings, defaults to a "+linter.Name+" directory in the user cache directory.")
+	d.listRules = f.Bool("list-rules", false, "List all rules and where they are enabled, then exit.")
+	d.enable = f.String("enable", "", "Comma separated list of rules to enable, as "+linter.Selectors+".")
+	d.disable = f.String("disable", "", "Comma separated list of rules to disable, as "+linter.Selectors+".")
+	return d
+}
+
+// Run runs the command of args, linting by default, and returns the exit code.
+func (d *Driver) Run(args []string) int {
+	var command *Command
+	if len(args) > 0 {
+		for i := range d.linter.Commands {
+			if d.linter.Commands[i].Name == args[0] {
+				command, args = &d.linter.Commands[i], args[1:]
+			}
+		}
+	}
+	fixedBaseline := command == nil && len(args) > 0 && args[0] == fixedBaselineCommand
+	if fixedBaseline {
+		args = args[1:]
+	}
+	_ = d.flags.Parse(args)
+
+	var failed bool
+	err := d.configureRules()
+	switch {
+	case err != nil:
+	case *d.listRules:
+		err = d.linter.WriteRules(os.Stdout)
+	case fixedBaseline:
+		err = d.listFixedBaseline(d.flags.Args())
+	case command != nil:
+		failed, err = command.Run(d, d.flags.Args())
+	default:
+		failed, err = d.lint(d.flags.Args())
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 2
+	}
+	if failed {
+		return 2
+	}
+	return 0
+}
+
+// Format returns the output format requested by -format.
+func (d *Driver) Format() string {
+	return *d.format
+}
+
+// configureRules sets the rules that apply to each kind of file from the -config file, then from
+// the -enable and -disable flags.
+func (d *Driver) configureRules() error {
+	if *d.configPath != "" {
+		config, err := LoadConfig(*d.configPath)
+		if err != nil {
+			return err
+		}
+		if err := d.linter.Configure(*d.configPath, config); err != nil {
+			return err
+		}
+		d.config = config
+	}
+	return d.linter.SelectRules(*d.enable, *d.disable)
+}
+
+// lint checks the given paths and writes the findings in the requested format. It returns true
+// if there are findings at or above the -fail-on severity.
+func (d *Driver) lint(paths []string) (bool, error) {
+	if err := ValidateFormat(*d.format); err != nil {
+		return false, err
+	}
+	threshold, err := ParseSeverity(*d.failOn)
+	if err != nil {
+		return false, err
+	}
+	report, err := d.check(paths, true)
+	if err != nil {
+		return false, err
+	}
+
+	if *d.writeBaseline != "" {
+		return false, NewBaseline(*d.writeBaseline, report).Write(*d.writeBaseline)
+	}
+	if *d.baselinePath != "" {
+		baseline, err := LoadBaseline(*d.baselinePath)
+		if err != nil {
+			return false, err
+		}
+		baseline.Filter(report)
+	}
+	changed, err := d.changedLines()
+	if err != nil {
+		return false, err
+	}
+	if changed != nil {
+		report.FilterChanged(changed)
+	}
+	if err := d.applyFixes(report); err != nil {
+		return false, err
+	}
+
+	out := os.Stdout
+	if *d.format == FormatText {
+		out = os.Stderr
+	}
+	if err := WriteReport(out, *d.format, d.linter.Name, report); err != nil {
+		return false, err
+	}
+	return report.HasFindingsAtLeast(threshold), nil
+}
+
+// listFixedBaseline prints the entries of the -baseline file which no longer have a finding in
+// the given paths, so that they can be removed from the file.
+func (d *Driver) listFixedBaseline(paths []string) error {
+	if *d.baselinePath == "" {
+		return fmt.Errorf("%s requires -baseline", fixedBaselineCommand)
+	}
+	baseline, err := LoadBaseline(*d.baselinePath)
+	if err != nil {
+		return err
+	}
+	report, err := d.check(paths, true)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range baseline.Fixed(report) {
+		fmt.Printf("%s: %s: %s (%d fixed)\n", e.File, e.Rule, e.Snippet, e.Count)
+	}
+	if *d.writeBaseline != "" {
+		baseline.Prune(report)
+		return baseline.Write(*d.writeBaseline)
+	}
+	return nil
+}
+
+// Report parses the flags in args, checks the paths that follow them without the cache, and
+// returns the resulting report.
+func (d *Driver) Report(args []string) (*Report, error) {
+	if err := d.flags.Parse(args); err != nil {
+		return nil, err
+	}
+	return d.check(d.flags.Args(), false)
+}
+
+// check checks the given paths and returns the resulting report. The cache of findings is used
+// if useCache is true and it is not disabled by -no-cache.
+func (d *Driver) check(paths []string, useCache bool) (*Report, error) {
+	whitelist := NewWhitelist(d.linter.Whitelist)
+	report := NewLintReport()
+	whitelist.AddEntries(d.config.Whitelist)
+
+	factory := AddRules(d.linter.Rules, d.config.BannedAPIRules()...)
+	opts := d.Options()
+	if useCache && !*d.noCache {
+		opts.Cache = d.newCache()
+	}
+	err := CheckWithOptions(paths, factory, whitelist, report, opts)
+	if err != nil {
+		return nil, err
+	}
+	whitelist.ReportExpired(report)
+	d.config.ApplySeverities(report)
+	return report, nil
+}
+
+// Options returns the options for CheckWithOptions set by the flags.
+func (d *Driver) Options() Options {
+	opts := Options{
+		Workers:          *d.workers,
+		IncludeGenerated: *d.includeGenerated,
+		GOOS:             *d.goos,
+		GOARCH:           *d.goarch,
+		LoadTypes:        *d.loadTypes,
+		OptionalTags:     d.linter.OptionalTags,
+	}
+	if *d.tags != "" {
+		opts.Tags = strings.Split(*d.tags, ",")
+	}
+	return opts
+}
+
+// newCache returns the cache of findings in -cache-dir, or nil if it is not available.
+func (d *Driver) newCache() *Cache {
+	dir := *d.cacheDir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultCacheDir(d.linter.Name); err != nil {
+			return nil
+		}
+	}
+	version, err := ExecutableVersion()
+	if err != nil {
+		return nil
+	}
+	return NewCache(dir, version)
+}
+
+// changedLines returns the lines findings are restricted to by the -new-from-rev or -new-from-patch
+// flags, or nil if neither is set.
+func (d *Driver) changedLines() (ChangedLines, error) {
+	if *d.newFromRev != "" {
+		return GitChangedLines(*d.newFromRev)
+	}
+	if *d.newFromPatch == "" {
+		return nil, nil
+	}
+	in := os.Stdin
+	if *d.newFromPatch != "-" {
+		f, err := os.Open(*d.newFromPatch)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return ParseUnifiedDiff(in, wd)
+}
+
+// applyFixes applies or prints the fixes suggested in report, as requested by the -fix and -diff flags.
+func (d *Driver) applyFixes(report *Report) error {
+	if !d.linter.Fixes {
+		return nil
+	}
+	if *d.diff {
+		return ApplyFixes(report, os.Stdout)
+	} else if *d.fix {
+		return ApplyFixes(report, nil)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"io"
+	"testing"
+)
+
+func testLinter(fixes bool, commands ...Command) Linter {
+	return Linter{
+		Name:        "testlint",
+		Rules:       goFiles{&noBad{}},
+		Selectors:   "rule_id",
+		Configure:   func(string, *Config) error { return nil },
+		SelectRules: func(string, string) error { return nil },
+		WriteRules:  func(io.Writer) error { return nil },
+		Fixes:       fixes,
+		Commands:    commands,
+	}
+}
+
+func TestDriverFixFlags(t *testing.T) {
+	for _, fixes := range []bool{false, true} {
+		d := NewDriver(testLinter(fixes))
+		for _, name := range []string{"fix", "diff"} {
+			if got := d.flags.Lookup(name) != nil; got != fixes {
+				t.Errorf("with Fixes %v, -%s is defined: %v", fixes, name, got)
+			}
+		}
+	}
+}
+
+func TestDriverCommand(t *testing.T) {
+	var got []string
+	command := Command{
+		Name: "list",
+		Run: func(d *Driver, paths []string) (bool, error) {
+			got = append(paths, d.Format())
+			return true, nil
+		},
+	}
+	d := NewDriver(testLinter(false, command))
+	if code := d.Run([]string{"list", "-format=json", "a", "b"}); code != 2 {
+		t.Errorf("exit code %d, want 2", code)
+	}
+	if want := []string{"a", "b", FormatJSON}; len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("command run with %v, want %v", got, want)
+	}
+}
+
+func TestDriverReport(t *testing.T) {
+	linter := testLinter(false)
+	report, err := NewDriver(linter).Report([]string{"testdata/suppress"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if countRule(report, "no_bad") == 0 {
+		t.Fatalf("no no_bad finding in %v", report.Items())
+	}
+
+	linter.Whitelist = map[string][]string{getAbsPath("testdata/suppress") + "/*": {"no_bad"}}
+	if report, err = NewDriver(linter).Report([]string{"testdata/suppress"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRule(report, "no_bad"); n != 0 {
+		t.Errorf("%d whitelisted findings reported: %v", n, report.Items())
+	}
+}
+
+func countRule(report *Report, id string) int {
+	n := 0
+	for _, f := range report.Findings() {
+		if f.RuleID == id {
+			n++
+		}
+	}
+	return n
+}
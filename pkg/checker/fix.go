@@ -0,0 +1,306 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ApplyFixes applies the edits suggested by the findings in report, and gofmt formats the
+// changed files. A finding is fixed only if none of its edits overlap an edit of a finding that
+// comes earlier in the file. Identical edits, such as an import added by the fixes of several
+// findings, do not overlap and are applied once.
+//
+// If diff is nil, the files are rewritten in place and the fixed findings are removed from
+// report. Otherwise a unified diff of the changes is written to diff, and neither the files nor
+// report are modified.
+func ApplyFixes(report *Report, diff io.Writer) error {
+	byFile := map[string][]int{}
+	var files []string
+	for i, f := range report.Findings() {
+		if len(f.Edits) == 0 || f.Pos.Filename == "" {
+			continue
+		}
+		if _, ok := byFile[f.Pos.Filename]; !ok {
+			files = append(files, f.Pos.Filename)
+		}
+		byFile[f.Pos.Filename] = append(byFile[f.Pos.Filename], i)
+	}
+
+	report.mu.Lock()
+	defer report.mu.Unlock()
+	fixed := map[int]bool{}
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var edits []TextEdit
+		for _, i := range byFile[file] {
+			f := report.findings[i]
+			if validEdits(f.Edits, len(src)) && !overlaps(edits, f.Edits) {
+				edits = append(edits, f.Edits...)
+				fixed[i] = true
+			}
+		}
+		if len(edits) == 0 {
+			continue
+		}
+
+		out, err := format.Source(applyEdits(src, edits))
+		if err != nil {
+			return fmt.Errorf("unable to format %s after applying fixes: %v", file, err)
+		}
+		if diff != nil {
+			if _, err := io.WriteString(diff, unifiedDiff(relativeURI(file), src, out)); err != nil {
+				return err
+			}
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file, out, info.Mode()); err != nil {
+			return err
+		}
+	}
+
+	if diff == nil {
+		remaining := report.findings[:0]
+		for i, f := range report.findings {
+			if !fixed[i] {
+				remaining = append(remaining, f)
+			}
+		}
+		report.findings = remaining
+	}
+	return nil
+}
+
+// validEdits returns true if all edits are within a file of the given size.
+func validEdits(edits []TextEdit, size int) bool {
+	for _, e := range edits {
+		if e.Offset < 0 || e.End < e.Offset || e.End > size {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns true if any edit in b overlaps an edit in a.
+func overlaps(a, b []TextEdit) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				continue
+			}
+			if x.Offset < y.End && y.Offset < x.End {
+				return true
+			}
+			// Two insertions at the same offset have no defined order.
+			if x.Offset == y.Offset && (x.Offset == x.End || y.Offset == y.End) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyEdits returns src with the non-overlapping edits applied, identical edits once.
+func applyEdits(src []byte, edits []TextEdit) []byte {
+	sorted := make([]TextEdit, len(edits))
+	copy(sorted, edits)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+
+	var out bytes.Buffer
+	last := 0
+	for i, e := range sorted {
+		if i > 0 && e == sorted[i-1] {
+			continue
+		}
+		out.Write(src[last:e.Offset])
+		out.WriteString(e.NewText)
+		last = e.End
+	}
+	out.Write(src[last:])
+	return out.Bytes()
+}
+
+// diffContext is the number of unchanged lines shown around each change in a unified diff.
+const diffContext = 3
+
+// diffOp is one line of a line based diff: an unchanged (' '), deleted ('-') or inserted ('+')
+// line.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns the changes from a to b as a unified diff of the file name, or an empty
+// string if they are equal.
+func unifiedDiff(name string, a, b []byte) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	// Positions in a and b of ops[i], 1-based.
+	aLine, bLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	aLine[0], bLine[0] = 1, 1
+	for i, op := range ops {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if op.kind != '+' {
+			aLine[i+1]++
+		}
+		if op.kind != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are close enough to share context.
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		stop := end + diffContext
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+		}
+		aCount, bCount := aLine[stop]-aLine[start], bLine[stop]-bLine[start]
+		aStart, bStart := aLine[start], bLine[start]
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, op := range ops[start:stop] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = stop
+	}
+	return out.String()
+}
+
+// splitLines splits src into lines, keeping the line endings.
+func splitLines(src []byte) []string {
+	var lines []string
+	for len(src) > 0 {
+		i := bytes.IndexByte(src, '\n') + 1
+		if i == 0 {
+			i = len(src)
+		}
+		lines = append(lines, string(src[:i]))
+		src = src[i:]
+	}
+	return lines
+}
+
+// maxDiffCells bounds the size of the table used to find the longest common subsequence of the
+// changed lines. Larger changes are shown as a deletion of all old lines followed by an insertion
+// of all new lines.
+const maxDiffCells = 1 << 22
+
+// diffLines returns a minimal line diff between a and b.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if (len(ma)+1)*(len(mb)+1) > maxDiffCells {
+		for _, line := range ma {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range mb {
+			ops = append(ops, diffOp{'+', line})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of ma[i:] and mb[j:].
+		lcs := make([][]int, len(ma)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(mb)+1)
+		}
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(ma) || j < len(mb) {
+			switch {
+			case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+				ops = append(ops, diffOp{' ', ma[i]})
+				i++
+				j++
+			case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+				ops = append(ops, diffOp{'-', ma[i]})
+				i++
+			default:
+				ops = append(ops, diffOp{'+', mb[j]})
+				j++
+			}
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// badToGood reports every call to bad(), and suggests calling good() instead.
+type badToGood struct{}
+
+func (lr *badToGood) GetID() string {
+	return "bad_to_good"
+}
+
+func (lr *badToGood) Check(aNode ast.Node, fs *token.FileSet, lrp *Report) {
+	if ce, ok := aNode.(*ast.CallExpr); ok {
+		if id, ok := ce.Fun.(*ast.Ident); ok && id.Name == "bad" {
+			lrp.AddItemWithFix(fs.Position(ce.Pos()), lr.GetID(), "bad() is disallowed.",
+				NewTextEdit(fs, id.Pos(), id.End(), "good"))
+		}
+	}
+}
+
+const fixSource = `package fix
+
+func bad()  {}
+func good() {}
+
+func f() {
+	bad()
+	println(1)
+	println(2)
+	println(3)
+	println(4)
+	bad()
+}
+`
+
+func writeFixSource(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "checker-fix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "fix.go")
+	if err := ioutil.WriteFile(path, []byte(fixSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApplyFixesDiff(t *testing.T) {
+	path := writeFixSource(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	report := NewLintReport()
+	if err := Check([]string{path}, goFiles{&badToGood{}}, NewWhitelist(nil), report); err != nil {
+		t.Fatal(err)
+	}
+	var diff bytes.Buffer
+	if err := ApplyFixes(report, &diff); err != nil {
+		t.Fatal(err)
+	}
+
+	name := relativeURI(path)
+	expected := "--- a/" + name + "\n+++ b/" + name + "\n" +
+		"@@ -4,10 +4,10 @@\n" +
+		" func good() {}\n" +
+		" \n" +
+		" func f() {\n" +
+		"-\tbad()\n" +
+		"+\tgood()\n" +
+		" \tprintln(1)\n" +
+		" \tprintln(2)\n" +
+		" \tprintln(3)\n" +
+		" \tprintln(4)\n" +
+		"-\tbad()\n" +
+		"+\tgood()\n" +
+		" }\n"
+	if diff.String() != expected {
+		t.Errorf("diff doesn't match\nReceived:\n%s\nExpected:\n%s", diff.String(), expected)
+	}
+	if len(report.Items()) != 2 {
+		t.Errorf("expected the findings to be kept, received: %v", report.Items())
+	}
+	if src, _ := ioutil.ReadFile(path); string(src) != fixSource {
+		t.Errorf("expected the file to be left unchanged, received:\n%s", src)
+	}
+}
+
+func TestApplyFixesInPlace(t *testing.T) {
+	path := writeFixSource(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	report := NewLintReport()
+	if err := Check([]string{path}, goFiles{&badToGood{}}, NewWhitelist(nil), report); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyFixes(report, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Items()) != 0 {
+		t.Errorf("expected the fixed findings to be removed, received: %v", report.Items())
+	}
+	report = NewLintReport()
+	if err := Check([]string{path}, goFiles{&badToGood{}}, NewWhitelist(nil), report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Items()) != 0 {
+		t.Errorf("expected no findings after fixing, received: %v", report.Items())
+	}
+}
+
+func TestOverlappingEdits(t *testing.T) {
+	a := []TextEdit{{Offset: 10, End: 20}}
+	for _, tc := range []struct {
+		edit     TextEdit
+		overlaps bool
+	}{
+		{TextEdit{Offset: 0, End: 10}, false},
+		{TextEdit{Offset: 20, End: 25}, false},
+		{TextEdit{Offset: 15, End: 25}, true},
+		{TextEdit{Offset: 12, End: 12}, true},
+		{TextEdit{Offset: 10, End: 10}, true},
+		{TextEdit{Offset: 10, End: 20}, false},
+	} {
+		if got := overlaps(a, []TextEdit{tc.edit}); got != tc.overlaps {
+			t.Errorf("overlaps(%v, %v) = %v, expected %v", a, tc.edit, got, tc.overlaps)
+		}
+	}
+}
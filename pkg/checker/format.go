@@ -0,0 +1,264 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Output formats supported by WriteReport.
+const (
+	FormatText       = "text"
+	FormatJSON       = "json"
+	FormatSARIF      = "sarif"
+	FormatCheckstyle = "checkstyle"
+)
+
+// ValidateFormat returns an error if format is not supported by WriteReport.
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON, FormatSARIF, FormatCheckstyle:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, must be one of %s, %s, %s or %s",
+		format, FormatText, FormatJSON, FormatSARIF, FormatCheckstyle)
+}
+
+// WriteReport writes the findings in report to w in the given format. tool is the name of the
+// linter, recorded by the formats which have a field for it.
+func WriteReport(w io.Writer, format string, tool string, report *Report) error {
+	findings := report.Findings()
+	switch format {
+	case FormatText:
+		return writeText(w, findings)
+	case FormatJSON:
+		return writeJSON(w, findings)
+	case FormatSARIF:
+		return writeSARIF(w, tool, findings)
+	case FormatCheckstyle:
+		return writeCheckstyle(w, tool, findings)
+	}
+	return ValidateFormat(format)
+}
+
+// writeText writes one finding per line, as file:line:col: severity: msg (id).
+func writeText(w io.Writer, findings []Finding) error {
+	for _, f := range findings {
+		line := f.Message
+		if f.RuleID != "" {
+			line = fmt.Sprintf("%v:%v:%v: %s: %s (%s)", f.Pos.Filename, f.Pos.Line, f.Pos.Column, f.Severity, f.Message, f.RuleID)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonFinding struct {
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Rule     string   `json:"rule,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func writeJSON(w io.Writer, findings []Finding) error {
+	out := make([]jsonFinding, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, jsonFinding{
+			File:     f.Pos.Filename,
+			Line:     f.Pos.Line,
+			Column:   f.Pos.Column,
+			Rule:     f.RuleID,
+			Severity: f.Severity,
+			Message:  f.Message,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// The subset of the SARIF 2.1.0 object model emitted by writeSARIF.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name  string      `json:"name"`
+		Rules []sarifRule `json:"rules,omitempty"`
+	}
+	sarifRule struct {
+		ID string `json:"id"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId,omitempty"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations,omitempty"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+)
+
+func writeSARIF(w io.Writer, tool string, findings []Finding) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: tool}},
+		Results: []sarifResult{},
+	}
+	seenRules := map[string]bool{}
+	for _, f := range findings {
+		if f.RuleID != "" && !seenRules[f.RuleID] {
+			seenRules[f.RuleID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: f.RuleID})
+		}
+		result := sarifResult{
+			RuleID:  f.RuleID,
+			Level:   sarifLevel(f.Severity),
+			Message: sarifMessage{Text: f.Message},
+		}
+		if f.Pos.Filename != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: relativeURI(f.Pos.Filename)},
+			}}
+			if f.Pos.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Pos.Line, StartColumn: f.Pos.Column}
+			}
+			result.Locations = []sarifLocation{loc}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	}
+	return "error"
+}
+
+// relativeURI returns path relative to the working directory, using forward slashes, so that
+// code scanning tools can map it to a file in the repository.
+func relativeURI(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// The Checkstyle XML format emitted by writeCheckstyle.
+type (
+	checkstyleReport struct {
+		XMLName xml.Name         `xml:"checkstyle"`
+		Version string           `xml:"version,attr"`
+		Files   []checkstyleFile `xml:"file"`
+	}
+	checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+	checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr,omitempty"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr,omitempty"`
+	}
+)
+
+// writeCheckstyle writes the findings grouped by file. Findings which are not tied to a file, such
+// as errors reading a file, are grouped under a file named after the tool.
+func writeCheckstyle(w io.Writer, tool string, findings []Finding) error {
+	out := checkstyleReport{Version: "5.0"}
+	toolFile := checkstyleFile{Name: tool}
+	// Findings are sorted by file, so each file forms a contiguous run.
+	for _, f := range findings {
+		e := checkstyleError{
+			Line:     f.Pos.Line,
+			Column:   f.Pos.Column,
+			Severity: string(f.Severity),
+			Message:  f.Message,
+			Source:   f.RuleID,
+		}
+		if f.Pos.Filename == "" {
+			toolFile.Errors = append(toolFile.Errors, e)
+			continue
+		}
+		if len(out.Files) == 0 || out.Files[len(out.Files)-1].Name != f.Pos.Filename {
+			out.Files = append(out.Files, checkstyleFile{Name: f.Pos.Filename})
+		}
+		file := &out.Files[len(out.Files)-1]
+		file.Errors = append(file.Errors, e)
+	}
+	if len(toolFile.Errors) > 0 {
+		out.Files = append(out.Files, toolFile)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func newFormatTestReport() *Report {
+	report := NewLintReport()
+	report.AddItem(token.Position{Filename: "/src/b_test.go", Line: 3, Column: 1}, "no_sleep", "time.Sleep() is disallowed.")
+	report.AddItem(token.Position{Filename: "/src/a_test.go", Line: 7, Column: 2}, "skip_issue", "missing issue")
+	return report
+}
+
+func TestWriteReportText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, FormatText, "lint", newFormatTestReport()); err != nil {
+		t.Fatal(err)
+	}
+	expected := "/src/a_test.go:7:2: error: missing issue (skip_issue)\n" +
+		"/src/b_test.go:3:1: error: time.Sleep() is disallowed. (no_sleep)\n"
+	if buf.String() != expected {
+		t.Errorf("text output doesn't match\nReceived: %q\nExpected: %q", buf.String(), expected)
+	}
+}
+
+func TestWriteReportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, FormatJSON, "lint", newFormatTestReport()); err != nil {
+		t.Fatal(err)
+	}
+	var out []jsonFinding
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(out) != 2 || out[0].File != "/src/a_test.go" || out[0].Line != 7 || out[0].Rule != "skip_issue" ||
+		out[0].Severity != SeverityError {
+		t.Errorf("unexpected JSON output: %+v", out)
+	}
+}
+
+func TestWriteReportSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, FormatSARIF, "lint", newFormatTestReport()); err != nil {
+		t.Fatal(err)
+	}
+	var out sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid SARIF output: %v", err)
+	}
+	if out.Version != "2.1.0" || len(out.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log: %+v", out)
+	}
+	run := out.Runs[0]
+	if run.Tool.Driver.Name != "lint" || len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("unexpected SARIF run: %+v", run)
+	}
+	if region := run.Results[1].Locations[0].PhysicalLocation.Region; region.StartLine != 3 || region.StartColumn != 1 {
+		t.Errorf("unexpected SARIF region: %+v", region)
+	}
+}
+
+func TestWriteReportCheckstyle(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, FormatCheckstyle, "lint", newFormatTestReport()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<checkstyle version="5.0">`,
+		`<file name="/src/a_test.go">`,
+		`<error line="7" column="2" severity="error" message="missing issue" source="skip_issue"></error>`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("checkstyle output is missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteReportCheckstyleWithoutFile(t *testing.T) {
+	report := newFormatTestReport()
+	report.AddString("unable to read /src/c_test.go")
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, FormatCheckstyle, "lint", report); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), `<file name="">`) {
+		t.Errorf("checkstyle output has a file without name:\n%s", buf.String())
+	}
+	want := `<file name="lint">
+    <error line="0" severity="error" message="unable to read /src/c_test.go"></error>
+  </file>`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("checkstyle output is missing %q:\n%s", want, buf.String())
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	if err := ValidateFormat("yaml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
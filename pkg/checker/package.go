@@ -0,0 +1,135 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+)
+
+// hasPackageRule returns true if any of rules is a PackageRule.
+func hasPackageRule(rules []Rule) bool {
+	for _, rule := range rules {
+		if _, ok := rule.(PackageRule); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// packageKey identifies a package by directory and package clause.
+type packageKey struct {
+	dir, name string
+}
+
+// checkPackages groups the visited files into packages, and runs each PackageRule on the files of
+// a package it applies to. Findings in the visited files are added to the visitor of their file,
+// so that they are suppressed as usual, and other findings are added to report.
+func checkPackages(visited []*FileVisitor, whitelist *Whitelist, report *Report) {
+	packages := map[packageKey][]*FileVisitor{}
+	var keys []packageKey
+	for _, v := range visited {
+		k := packageKey{dir: filepath.Dir(v.path), name: v.file.Name.Name}
+		if _, ok := packages[k]; !ok {
+			keys = append(keys, k)
+		}
+		packages[k] = append(packages[k], v)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].dir != keys[j].dir {
+			return keys[i].dir < keys[j].dir
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	for _, k := range keys {
+		files := packages[k]
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].path < files[j].path
+		})
+		byPath := map[string]*FileVisitor{}
+		for _, v := range files {
+			byPath[v.path] = v
+		}
+
+		for _, rule := range packageRules(files) {
+			var applied []*FileVisitor
+			for _, v := range files {
+				for _, r := range v.rules {
+					if r.GetID() == rule.GetID() {
+						applied = append(applied, v)
+						break
+					}
+				}
+			}
+			fs, syntax := packageSyntax(applied)
+			pkgReport := NewLintReport()
+			rule.CheckPackage(syntax, fs, pkgReport)
+			setDefaultSeverities([]Rule{rule}, pkgReport)
+
+			for _, f := range pkgReport.Findings() {
+				if f.Pos.Filename != "" && whitelist.Apply(f.Pos.Filename, rule) {
+					continue
+				}
+				if v, ok := byPath[f.Pos.Filename]; ok {
+					v.report.AddFinding(f)
+				} else {
+					report.AddFinding(f)
+				}
+			}
+		}
+	}
+}
+
+// packageRules returns the distinct PackageRules of files, by rule ID.
+func packageRules(files []*FileVisitor) []PackageRule {
+	seen := map[string]bool{}
+	var rules []PackageRule
+	for _, v := range files {
+		for _, rule := range v.rules {
+			if pr, ok := rule.(PackageRule); ok && !seen[rule.GetID()] {
+				seen[rule.GetID()] = true
+				rules = append(rules, pr)
+			}
+		}
+	}
+	return rules
+}
+
+// packageSyntax returns the syntax trees of the visited files, with a FileSet holding all of them.
+// Files parsed on their own are parsed again into a shared FileSet.
+func packageSyntax(files []*FileVisitor) (*token.FileSet, []*ast.File) {
+	syntax := make([]*ast.File, 0, len(files))
+	shared := true
+	for _, v := range files {
+		shared = shared && v.fileset == files[0].fileset
+		syntax = append(syntax, v.file)
+	}
+	if shared && len(files) > 0 {
+		return files[0].fileset, syntax
+	}
+
+	fs := token.NewFileSet()
+	syntax = syntax[:0]
+	for _, v := range files {
+		if f, err := parser.ParseFile(fs, v.path, nil, parser.ParseComments); err == nil {
+			syntax = append(syntax, f)
+		}
+	}
+	return fs, syntax
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+// packageFiles reports the number of files of the package at the package clause of each file.
+type packageFiles struct{}
+
+func (lr *packageFiles) GetID() string {
+	return "package_files"
+}
+
+func (lr *packageFiles) Check(aNode ast.Node, fs *token.FileSet, lrp *Report) {}
+
+func (lr *packageFiles) CheckPackage(files []*ast.File, fs *token.FileSet, lrp *Report) {
+	for _, f := range files {
+		lrp.AddItem(fs.Position(f.Name.Pos()), lr.GetID(), fmt.Sprintf("package %s: %d files", f.Name.Name, len(files)))
+	}
+}
+
+func TestPackageRule(t *testing.T) {
+	report := NewLintReport()
+	if err := Check([]string{"testdata/packages"}, goFiles{&packageFiles{}}, NewWhitelist(nil), report); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRpts := []string{
+		getAbsPath("testdata/packages/a.go") + ":15:9:package p: 2 files (package_files)",
+		getAbsPath("testdata/packages/a_test.go") + ":15:9:package p_test: 1 files (package_files)",
+	}
+	if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+}
+
+func TestPackageRuleWhitelist(t *testing.T) {
+	whitelist := NewWhitelist(map[string][]string{getAbsPath("testdata/packages/a_test.go"): {"package_files"}})
+	report := NewLintReport()
+	if err := Check([]string{"testdata/packages"}, goFiles{&packageFiles{}}, whitelist, report); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRpts := []string{getAbsPath("testdata/packages/a.go") + ":15:9:package p: 2 files (package_files)"}
+	if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+}
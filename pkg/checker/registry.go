@@ -0,0 +1,271 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// RuleInfo describes a rule of a Registry.
+type RuleInfo struct {
+	// ID is the ID of the rule. It is filled in from the rule created by New if empty.
+	ID string
+	// Description is a one line description of what the rule reports.
+	Description string
+	// Kinds are the kinds of files, as defined by the linter, that the rule can apply to.
+	Kinds []string
+	// New creates the rule.
+	New func() Rule
+}
+
+// Registry holds the rules of a linter, so that they can be listed and selected by ID without
+// recompiling the linter.
+type Registry struct {
+	kinds []string
+	rules map[string]RuleInfo
+}
+
+// RuleSelector selects the rule with ID for the files of Kind, or for all the kinds of the rule
+// if Kind is empty.
+type RuleSelector struct {
+	Kind string
+	ID   string
+}
+
+// NewRegistry returns a registry of the given rules, for a linter which sorts files into the
+// given kinds. It panics if two rules have the same ID or a rule has an unknown kind, as
+// registries are built from static tables.
+func NewRegistry(kinds []string, rules ...RuleInfo) *Registry {
+	r := &Registry{kinds: kinds, rules: map[string]RuleInfo{}}
+	for _, info := range rules {
+		if info.ID == "" {
+			info.ID = info.New().GetID()
+		}
+		if _, ok := r.rules[info.ID]; ok {
+			panic(fmt.Sprintf("rule %s is registered twice", info.ID))
+		}
+		for _, kind := range info.Kinds {
+			if !r.validKind(kind) {
+				panic(fmt.Sprintf("rule %s has unknown kind %s", info.ID, kind))
+			}
+		}
+		r.rules[info.ID] = info
+	}
+	return r
+}
+
+// Kinds returns the kinds of files of the linter.
+func (r *Registry) Kinds() []string {
+	return r.kinds
+}
+
+// Rules returns the registered rules, sorted by ID.
+func (r *Registry) Rules() []RuleInfo {
+	rules := make([]RuleInfo, 0, len(r.rules))
+	for _, info := range r.rules {
+		rules = append(rules, info)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
+
+// Lookup returns the rule with the given ID.
+func (r *Registry) Lookup(id string) (RuleInfo, bool) {
+	info, ok := r.rules[id]
+	return info, ok
+}
+
+// SetRule makes the registry return rule, such as a rule configured from a config file, instead of
+// creating the registered rule with the same ID. The rules with the same ID in lists, such as the
+// default rules of the linter, are replaced by rule as well.
+func (r *Registry) SetRule(rule Rule, lists ...[]Rule) error {
+	info, ok := r.rules[rule.GetID()]
+	if !ok {
+		return fmt.Errorf("unknown rule %q", rule.GetID())
+	}
+	info.New = func() Rule { return rule }
+	r.rules[info.ID] = info
+	for _, rules := range lists {
+		for i, rr := range rules {
+			if rr.GetID() == info.ID {
+				rules[i] = rule
+			}
+		}
+	}
+	return nil
+}
+
+// NewRules creates the rules with the given IDs for the files of kind, such as the ones listed
+// for kind in the rules section of a config file.
+func (r *Registry) NewRules(kind string, ids []string) ([]Rule, error) {
+	if !r.validKind(kind) {
+		return nil, fmt.Errorf("unknown kind of files %q, expected one of %s", kind, strings.Join(r.kinds, ", "))
+	}
+	rules := make([]Rule, 0, len(ids))
+	for _, id := range ids {
+		info, ok := r.rules[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule %q", id)
+		}
+		if !info.hasKind(kind) {
+			return nil, fmt.Errorf("rule %s does not apply to %q files, only to %s", id, kind,
+				strings.Join(info.Kinds, ", "))
+		}
+		rules = append(rules, info.New())
+	}
+	return rules, nil
+}
+
+// ParseSelectors parses a comma separated list of rule_id or kind:rule_id, such as the value of
+// an -enable or -disable flag.
+func (r *Registry) ParseSelectors(list string) ([]RuleSelector, error) {
+	var selectors []RuleSelector
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		var s RuleSelector
+		if i := strings.IndexByte(item, ':'); i >= 0 {
+			s.Kind, s.ID = item[:i], item[i+1:]
+		} else {
+			s.ID = item
+		}
+		info, ok := r.rules[s.ID]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule %q", s.ID)
+		}
+		if s.Kind != "" && !info.hasKind(s.Kind) {
+			return nil, fmt.Errorf("rule %s does not apply to %q files, only to %s", s.ID, s.Kind,
+				strings.Join(info.Kinds, ", "))
+		}
+		selectors = append(selectors, s)
+	}
+	return selectors, nil
+}
+
+// AllRules returns a RulesFactory which returns all the registered rules that can apply to the
+// kind of each file, whether they are enabled in the linter or not. kind returns the kind of a file,
+// and false for the files the linter does not check. Rules are created when AllRules is called, so
+// that they have the settings of the registry at that time.
+func (r *Registry) AllRules(kind func(absp string, info os.FileInfo) (string, bool)) RulesFactory {
+	byKind := map[string][]Rule{}
+	for _, info := range r.Rules() {
+		rule := info.New()
+		for _, k := range info.Kinds {
+			byKind[k] = append(byKind[k], rule)
+		}
+	}
+	return &kindRules{kind: kind, rules: byKind}
+}
+
+type kindRules struct {
+	kind  func(absp string, info os.FileInfo) (string, bool)
+	rules map[string][]Rule
+}
+
+func (k *kindRules) GetRules(absp string, info os.FileInfo) []Rule {
+	if kind, ok := k.kind(absp, info); ok {
+		return k.rules[kind]
+	}
+	return nil
+}
+
+// Select returns the rules for the files of kind: the defaults, plus the rules selected by enable
+// and minus the ones selected by disable. Enabled rules are created with their New function.
+func (r *Registry) Select(kind string, defaults []Rule, enable, disable []RuleSelector) []Rule {
+	rules := make([]Rule, 0, len(defaults))
+	present := map[string]bool{}
+	for _, rule := range defaults {
+		rules = append(rules, rule)
+		present[rule.GetID()] = true
+	}
+	for _, s := range enable {
+		if info := r.rules[s.ID]; r.selects(s, kind) && !present[s.ID] {
+			rules = append(rules, info.New())
+			present[s.ID] = true
+		}
+	}
+	selected := rules[:0]
+	for _, rule := range rules {
+		disabled := false
+		for _, s := range disable {
+			disabled = disabled || (s.ID == rule.GetID() && r.selects(s, kind))
+		}
+		if !disabled {
+			selected = append(selected, rule)
+		}
+	}
+	return selected
+}
+
+// WriteRules writes a table of the registered rules to w, with the kinds of files each rule is
+// enabled for according to enabled, which maps kinds to their rules, the kinds it can apply to
+// and its default severity.
+func (r *Registry) WriteRules(w io.Writer, enabled map[string][]Rule) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tENABLED\tKINDS\tSEVERITY\tDESCRIPTION")
+	for _, info := range r.Rules() {
+		var on []string
+		for _, kind := range r.kinds {
+			for _, rule := range enabled[kind] {
+				if rule.GetID() == info.ID {
+					on = append(on, kind)
+					break
+				}
+			}
+		}
+		if len(on) == 0 {
+			on = []string{"-"}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", info.ID, strings.Join(on, ","), strings.Join(info.Kinds, ","),
+			DefaultSeverity(info.New()), info.Description)
+	}
+	return tw.Flush()
+}
+
+// selects returns true if s selects its rule for the files of kind.
+func (r *Registry) selects(s RuleSelector, kind string) bool {
+	if s.Kind != "" {
+		return s.Kind == kind
+	}
+	info, ok := r.rules[s.ID]
+	return ok && info.hasKind(kind)
+}
+
+func (r *Registry) validKind(kind string) bool {
+	for _, k := range r.kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (info RuleInfo) hasKind(kind string) bool {
+	for _, k := range info.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
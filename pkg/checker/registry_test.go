@@ -0,0 +1,176 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestRegistry() *Registry {
+	return NewRegistry([]string{"unit", "e2e"},
+		RuleInfo{ID: "a", Description: "Rule a.", Kinds: []string{"unit", "e2e"}, New: func() Rule { return namedRule("a") }},
+		RuleInfo{ID: "b", Description: "Rule b.", Kinds: []string{"e2e"}, New: func() Rule { return namedRule("b") }},
+		RuleInfo{Description: "Rule c.", Kinds: []string{"unit"}, New: func() Rule { return namedRule("c") }},
+	)
+}
+
+func ruleIDs(rules []Rule) []string {
+	ids := []string{}
+	for _, r := range rules {
+		ids = append(ids, r.GetID())
+	}
+	return ids
+}
+
+func TestRegistrySelect(t *testing.T) {
+	r := newTestRegistry()
+	defaults := []Rule{namedRule("a")}
+	cases := []struct {
+		enable, disable string
+		unit, e2e       []string
+	}{
+		{unit: []string{"a"}, e2e: []string{"a"}},
+		{enable: "b,c", unit: []string{"a", "c"}, e2e: []string{"a", "b"}},
+		{enable: "unit:c, e2e:b", disable: "unit:a", unit: []string{"c"}, e2e: []string{"a", "b"}},
+		{enable: "a", disable: "a", unit: []string{}, e2e: []string{}},
+	}
+	for _, c := range cases {
+		enable, err := r.ParseSelectors(c.enable)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", c.enable, err)
+		}
+		disable, err := r.ParseSelectors(c.disable)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", c.disable, err)
+		}
+		if unit := ruleIDs(r.Select("unit", defaults, enable, disable)); !reflect.DeepEqual(unit, c.unit) {
+			t.Errorf("-enable=%q -disable=%q: unit rules are %v, expected %v", c.enable, c.disable, unit, c.unit)
+		}
+		if e2e := ruleIDs(r.Select("e2e", defaults, enable, disable)); !reflect.DeepEqual(e2e, c.e2e) {
+			t.Errorf("-enable=%q -disable=%q: e2e rules are %v, expected %v", c.enable, c.disable, e2e, c.e2e)
+		}
+	}
+}
+
+func TestRegistryParseSelectorsErrors(t *testing.T) {
+	r := newTestRegistry()
+	for _, list := range []string{"d", "unit:b", "integration:a"} {
+		if _, err := r.ParseSelectors(list); err == nil {
+			t.Errorf("expected an error for %q", list)
+		}
+	}
+}
+
+func TestRegistryWriteRules(t *testing.T) {
+	r := newTestRegistry()
+	var out bytes.Buffer
+	if err := r.WriteRules(&out, map[string][]Rule{"e2e": {namedRule("a"), namedRule("b")}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"RULE  ENABLED  KINDS     SEVERITY  DESCRIPTION",
+		"a     e2e      unit,e2e  error     Rule a.",
+		"b     e2e      e2e       error     Rule b.",
+		"c     -        unit      error     Rule c.",
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("rules table doesn't match\nReceived: %q\nExpected: %q", lines, expected)
+	}
+
+	out.Reset()
+	r = NewRegistry([]string{"unit"}, RuleInfo{Description: "No bad.", Kinds: []string{"unit"}, New: func() Rule { return &warnBad{} }})
+	if err := r.WriteRules(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if line := strings.Split(out.String(), "\n")[1]; !strings.Contains(line, " warning ") {
+		t.Errorf("expected the declared severity in %q", line)
+	}
+}
+
+func TestRegistryNewRules(t *testing.T) {
+	r := newTestRegistry()
+	rules, err := r.NewRules("e2e", []string{"b", "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := ruleIDs(rules); !reflect.DeepEqual(ids, []string{"b", "a"}) {
+		t.Errorf("rules are %v, expected [b a]", ids)
+	}
+	for _, c := range []struct {
+		kind string
+		ids  []string
+	}{
+		{"integration", []string{"a"}},
+		{"unit", []string{"b"}},
+		{"unit", []string{"d"}},
+	} {
+		if _, err := r.NewRules(c.kind, c.ids); err == nil {
+			t.Errorf("expected an error for %s rules %v", c.kind, c.ids)
+		}
+	}
+}
+
+// configuredRule is a rule set with SetRule, with the ID of the registered rule.
+type configuredRule struct {
+	namedRule
+}
+
+func TestRegistrySetRule(t *testing.T) {
+	r := newTestRegistry()
+	rule := configuredRule{"a"}
+	defaults := []Rule{namedRule("a"), namedRule("b")}
+	if err := r.SetRule(rule, defaults); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []Rule{rule, namedRule("b")}; !reflect.DeepEqual(defaults, expected) {
+		t.Errorf("rules are %v, expected %v", defaults, expected)
+	}
+	rules, err := r.NewRules("unit", []string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0] != Rule(rule) {
+		t.Errorf("registry creates %v, expected the configured rule", rules)
+	}
+	if err := r.SetRule(namedRule("d")); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}
+
+func TestRegistryAllRules(t *testing.T) {
+	factory := newTestRegistry().AllRules(func(absp string, info os.FileInfo) (string, bool) {
+		switch filepath.Base(absp) {
+		case "unit_test.go":
+			return "unit", true
+		case "e2e_test.go":
+			return "e2e", true
+		}
+		return "", false
+	})
+	for path, expected := range map[string][]string{
+		"unit_test.go": {"a", "c"},
+		"e2e_test.go":  {"a", "b"},
+		"main.go":      {},
+	} {
+		if ids := ruleIDs(factory.GetRules(path, nil)); !reflect.DeepEqual(ids, expected) {
+			t.Errorf("rules of %s are %v, expected %v", path, ids, expected)
+		}
+	}
+}
@@ -17,11 +17,87 @@ package checker
 import (
 	"fmt"
 	"go/token"
+	"sort"
+	"sync"
 )
 
-// Report populates lint report.
+// Severity describes how serious a finding is.
+type Severity string
+
+// Severity levels of findings.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// severityRanks orders the severities, from the least to the most serious.
+var severityRanks = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// ParseSeverity returns the severity named s.
+func ParseSeverity(s string) (Severity, error) {
+	if _, ok := severityRanks[Severity(s)]; !ok {
+		return "", fmt.Errorf("unknown severity %q, must be one of %s, %s or %s",
+			s, SeverityError, SeverityWarning, SeverityInfo)
+	}
+	return Severity(s), nil
+}
+
+// AtLeast returns true if s is as serious as threshold, or more.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return severityRanks[s] >= severityRanks[threshold]
+}
+
+// Finding is a single problem reported by a rule.
+type Finding struct {
+	// Pos is the position of the problem. Findings that are not tied to a file have a zero Pos.
+	Pos token.Position
+	// RuleID is the ID of the rule that reported the finding, or empty for plain messages.
+	RuleID   string
+	Message  string
+	Severity Severity
+	// Edits is an optional fix for the problem, applied all together or not at all.
+	Edits []TextEdit
+}
+
+// TextEdit is a suggested change to a file, replacing the bytes from Offset up to End with NewText.
+type TextEdit struct {
+	Offset  int
+	End     int
+	NewText string
+}
+
+// NewTextEdit returns a TextEdit replacing the source between pos and end with newText.
+func NewTextEdit(fs *token.FileSet, pos, end token.Pos, newText string) TextEdit {
+	return TextEdit{
+		Offset:  fs.Position(pos).Offset,
+		End:     fs.Position(end).Offset,
+		NewText: newText,
+	}
+}
+
+// String formats the finding as file:line:col:msg (id). It does not include the severity, see
+// WriteReport for the formats which do.
+func (f Finding) String() string {
+	if f.RuleID == "" {
+		return f.Message
+	}
+	return fmt.Sprintf("%v:%v:%v:%s (%s)",
+		f.Pos.Filename,
+		f.Pos.Line,
+		f.Pos.Column,
+		f.Message,
+		f.RuleID)
+}
+
+// Report populates lint report. It is safe for concurrent use.
 type Report struct {
-	items []string
+	mu       sync.Mutex
+	findings []Finding
 }
 
 // NewLintReport creates and returns a Report object.
@@ -29,23 +105,118 @@ func NewLintReport() *Report {
 	return &Report{}
 }
 
-// Items returns formatted report as a string slice.
+// Findings returns the findings in the report, sorted by file, line and column.
+func (lr *Report) Findings() []Finding {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	sort.SliceStable(lr.findings, func(i, j int) bool {
+		a, b := lr.findings[i], lr.findings[j]
+		if a.Pos.Filename != b.Pos.Filename {
+			return a.Pos.Filename < b.Pos.Filename
+		}
+		if a.Pos.Line != b.Pos.Line {
+			return a.Pos.Line < b.Pos.Line
+		}
+		if a.Pos.Column != b.Pos.Column {
+			return a.Pos.Column < b.Pos.Column
+		}
+		if a.RuleID != b.RuleID {
+			return a.RuleID < b.RuleID
+		}
+		return a.Message < b.Message
+	})
+	findings := make([]Finding, len(lr.findings))
+	copy(findings, lr.findings)
+	return findings
+}
+
+// Items returns formatted report as a string slice, sorted by file, line and column.
 func (lr *Report) Items() []string {
-	return lr.items
+	findings := lr.Findings()
+	items := make([]string, 0, len(findings))
+	for _, f := range findings {
+		items = append(items, f.String())
+	}
+	return items
 }
 
 // AddItem creates a new lint error report.
 func (lr *Report) AddItem(pos token.Position, id string, msg string) {
-	item := fmt.Sprintf("%v:%v:%v:%s (%s)",
-		pos.Filename,
-		pos.Line,
-		pos.Column,
-		msg,
-		id)
-	lr.AddString(item)
+	lr.AddFinding(Finding{
+		Pos:      pos,
+		RuleID:   id,
+		Message:  msg,
+		Severity: SeverityError,
+	})
+}
+
+// AddItemWithFix creates a new lint error report with a suggested fix.
+func (lr *Report) AddItemWithFix(pos token.Position, id string, msg string, edits ...TextEdit) {
+	lr.AddFinding(Finding{
+		Pos:      pos,
+		RuleID:   id,
+		Message:  msg,
+		Severity: SeverityError,
+		Edits:    edits,
+	})
 }
 
 // AddString creates a new string line in report.
 func (lr *Report) AddString(msg string) {
-	lr.items = append(lr.items, msg)
+	lr.AddFinding(Finding{
+		Message:  msg,
+		Severity: SeverityError,
+	})
+}
+
+// AddFinding adds f to the report.
+func (lr *Report) AddFinding(f Finding) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.findings = append(lr.findings, f)
+}
+
+// Filter removes the findings for which keep returns false from the report.
+func (lr *Report) Filter(keep func(Finding) bool) {
+	findings := lr.Findings()
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.findings = lr.findings[:0]
+	for _, f := range findings {
+		if keep(f) {
+			lr.findings = append(lr.findings, f)
+		}
+	}
+}
+
+// HasFindingsAtLeast returns true if the report has a finding with the threshold severity, or a
+// more serious one.
+func (lr *Report) HasFindingsAtLeast(threshold Severity) bool {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	for _, f := range lr.findings {
+		if f.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// setSeverities sets the severity of each finding to the one returned by severity.
+func (lr *Report) setSeverities(severity func(Finding) Severity) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	for i, f := range lr.findings {
+		lr.findings[i].Severity = severity(f)
+	}
+}
+
+// FilterChanged removes the findings on lines that are not in changed from the report. Findings
+// which are not tied to a file are kept.
+func (lr *Report) FilterChanged(changed ChangedLines) {
+	lr.Filter(func(f Finding) bool {
+		return f.Pos.Filename == "" || changed.Contains(f.Pos.Filename, f.Pos.Line)
+	})
 }
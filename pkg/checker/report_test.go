@@ -0,0 +1,78 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"go/token"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestReportItemsSorted(t *testing.T) {
+	positions := []token.Position{
+		{Filename: "b.go", Line: 1, Column: 1},
+		{Filename: "a.go", Line: 10, Column: 2},
+		{Filename: "a.go", Line: 2, Column: 5},
+		{Filename: "a.go", Line: 2, Column: 3},
+	}
+
+	report := NewLintReport()
+	var wg sync.WaitGroup
+	for _, pos := range positions {
+		wg.Add(1)
+		//lint:ignore no_goroutine findings are added concurrently on purpose, to test that they are sorted.
+		go func(pos token.Position) {
+			defer wg.Done()
+			report.AddItem(pos, "rule", "msg")
+		}(pos)
+	}
+	wg.Wait()
+
+	expected := []string{
+		"a.go:2:3:msg (rule)",
+		"a.go:2:5:msg (rule)",
+		"a.go:10:2:msg (rule)",
+		"b.go:1:1:msg (rule)",
+	}
+	if items := report.Items(); !reflect.DeepEqual(items, expected) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", items, expected)
+	}
+}
+
+func TestHasFindingsAtLeast(t *testing.T) {
+	report := NewLintReport()
+	report.AddFinding(Finding{Pos: token.Position{Filename: "a.go", Line: 1}, RuleID: "rule", Message: "msg",
+		Severity: SeverityWarning})
+
+	for threshold, expected := range map[Severity]bool{
+		SeverityInfo:    true,
+		SeverityWarning: true,
+		SeverityError:   false,
+	} {
+		if got := report.HasFindingsAtLeast(threshold); got != expected {
+			t.Errorf("HasFindingsAtLeast(%s) = %v, expected %v", threshold, got, expected)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	if s, err := ParseSeverity("warning"); err != nil || s != SeverityWarning {
+		t.Errorf("ParseSeverity(warning) = %v, %v", s, err)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
@@ -17,6 +17,7 @@ package checker
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"os"
 )
 
@@ -28,8 +29,90 @@ type Rule interface {
 	Check(aNode ast.Node, fs *token.FileSet, lrp *Report)
 }
 
+// TypedRule is a Rule which also uses type information. When a RulesFactory returns a TypedRule
+// for a file, Check loads and type checks the package of that file, and calls CheckTyped instead of
+// Check. If the package cannot be loaded, Check is called as for any other Rule.
+type TypedRule interface {
+	Rule
+	// CheckTyped verifies if aNode passes rule check, using the type information of the package.
+	// If verification fails lrp creates a report.
+	CheckTyped(aNode ast.Node, fs *token.FileSet, info *types.Info, pkg *types.Package, lrp *Report)
+}
+
+// OptionalTypedRule is a TypedRule whose Check does not need type information, which only makes
+// CheckTyped more precise. Check does not load the package of a file for such a rule, and calls
+// CheckTyped only if the package is loaded for another rule of the file or Options.LoadTypes is set,
+// so that type checking is not paid for by default.
+type OptionalTypedRule interface {
+	TypedRule
+	// TypesOptional returns true if Check can be called instead of CheckTyped without type
+	// information being loaded.
+	TypesOptional() bool
+}
+
+// PackageRule is a Rule which also checks whole packages. After the files of a package are
+// visited, Check calls CheckPackage once with all the files of the package for which the
+// RulesFactory returns the rule. Files are grouped into packages by directory and package clause,
+// so the external test package of a directory is a package of its own. Findings can be reported
+// at any position, and are whitelisted and suppressed like the findings of other rules.
+type PackageRule interface {
+	Rule
+	// CheckPackage verifies if the files of a package pass rule check. If verification fails lrp
+	// creates a report.
+	CheckPackage(files []*ast.File, fs *token.FileSet, lrp *Report)
+}
+
+// ConfigRule is a Rule with settings which change its findings. The settings are part of the key
+// of the findings cached for a file, so that files are checked again when the settings change.
+// The findings of rules with settings which do not implement it are cached regardless of them.
+type ConfigRule interface {
+	Rule
+	// GetConfig returns the settings of the rule as a string, which changes whenever they do.
+	GetConfig() string
+}
+
+// SeverityRule is a Rule which declares the default severity of its findings. Findings of rules
+// which do not implement it are errors. The severity can be overridden per rule and path by the
+// configuration file.
+type SeverityRule interface {
+	Rule
+	// GetSeverity returns the default severity of the findings of the rule.
+	GetSeverity() Severity
+}
+
+// DefaultSeverity returns the severity of the findings of rule unless the configuration file
+// overrides it: the one it declares if it is a SeverityRule, error otherwise.
+func DefaultSeverity(rule Rule) Severity {
+	if sr, ok := rule.(SeverityRule); ok {
+		return sr.GetSeverity()
+	}
+	return SeverityError
+}
+
 // RulesFactory is interface to get Rules from a file path.
 type RulesFactory interface {
 	// GetRules returns a list of rules used to check against the files.
 	GetRules(absp string, info os.FileInfo) []Rule
 }
+
+// AddRules returns a RulesFactory which adds rules to the ones factory returns, for the files
+// factory returns rules for.
+func AddRules(factory RulesFactory, rules ...Rule) RulesFactory {
+	if len(rules) == 0 {
+		return factory
+	}
+	return &addedRules{factory: factory, rules: rules}
+}
+
+type addedRules struct {
+	factory RulesFactory
+	rules   []Rule
+}
+
+func (a *addedRules) GetRules(absp string, info os.FileInfo) []Rule {
+	rules := a.factory.GetRules(absp, info)
+	if len(rules) == 0 {
+		return rules
+	}
+	return append(rules[:len(rules):len(rules)], a.rules...)
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+const (
+	ignoreDirective     = "//lint:ignore"
+	fileIgnoreDirective = "//lint:file-ignore"
+
+	// SuppressionRuleID is the rule ID of findings about malformed or unused suppression comments.
+	SuppressionRuleID = "lint_ignore"
+)
+
+// suppression is a //lint:ignore or //lint:file-ignore comment which silences findings of the
+// named rules.
+//
+// A //lint:ignore comment covers the line it is on when it trails code, otherwise the line
+// that follows it. If a declaration or statement starts on the covered line, all of its lines
+// are covered, so a comment above a function silences the whole function.
+// A //lint:file-ignore comment covers the whole file.
+type suppression struct {
+	pos        token.Position
+	directive  string
+	ruleIDs    []string
+	reason     string
+	fileWide   bool
+	start, end int
+	matched    bool
+}
+
+// parseSuppressions returns the suppression comments in file which name at least one of the
+// given rules. Comments naming only other rules are left alone, since they likely belong to a
+// different linter using the same syntax.
+func parseSuppressions(fs *token.FileSet, file *ast.File, src []byte, rules []Rule) []*suppression {
+	known := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		known[rule.GetID()] = true
+	}
+
+	var sups []*suppression
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			s := newSuppression(fs, c)
+			if s == nil {
+				continue
+			}
+			relevant := len(s.ruleIDs) == 0
+			for _, id := range s.ruleIDs {
+				relevant = relevant || known[id]
+			}
+			if !relevant {
+				continue
+			}
+			if !s.fileWide {
+				s.start = s.pos.Line
+				if !trailsCode(fs, c, src) {
+					s.start = fs.Position(group.End()).Line + 1
+				}
+				s.end = nodesEndLine(fs, file, s.start)
+			}
+			sups = append(sups, s)
+		}
+	}
+	return sups
+}
+
+// newSuppression parses c, and returns nil if it is not a suppression comment.
+func newSuppression(fs *token.FileSet, c *ast.Comment) *suppression {
+	var s suppression
+	var rest string
+	switch {
+	case strings.HasPrefix(c.Text, fileIgnoreDirective):
+		s.directive, s.fileWide = fileIgnoreDirective, true
+		rest = strings.TrimPrefix(c.Text, fileIgnoreDirective)
+	case strings.HasPrefix(c.Text, ignoreDirective):
+		s.directive = ignoreDirective
+		rest = strings.TrimPrefix(c.Text, ignoreDirective)
+	default:
+		return nil
+	}
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		// Some other directive sharing the prefix.
+		return nil
+	}
+
+	s.pos = fs.Position(c.Pos())
+	if fields := strings.Fields(rest); len(fields) > 0 {
+		s.ruleIDs = strings.Split(fields[0], ",")
+		s.reason = strings.Join(fields[1:], " ")
+	}
+	return &s
+}
+
+// trailsCode returns true if there is code before comment c on its line.
+func trailsCode(fs *token.FileSet, c *ast.Comment, src []byte) bool {
+	tf := fs.File(c.Pos())
+	lineStart := tf.Offset(tf.LineStart(fs.Position(c.Pos()).Line))
+	return strings.TrimSpace(string(src[lineStart:tf.Offset(c.Pos())])) != ""
+}
+
+// nodesEndLine returns the last line of the declarations and statements starting on line, or
+// line itself if there are none.
+func nodesEndLine(fs *token.FileSet, file *ast.File, line int) int {
+	end := line
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		startLine := fs.Position(n.Pos()).Line
+		if startLine > line {
+			return false
+		}
+		if fs.Position(n.End()).Line < line {
+			return false
+		}
+		switch n.(type) {
+		case ast.Decl, ast.Stmt:
+			if endLine := fs.Position(n.End()).Line; startLine == line && endLine > end {
+				end = endLine
+			}
+		}
+		return true
+	})
+	return end
+}
+
+// covers returns true if s silences f.
+func (s *suppression) covers(f Finding) bool {
+	ruleMatches := false
+	for _, id := range s.ruleIDs {
+		ruleMatches = ruleMatches || id == f.RuleID
+	}
+	if !ruleMatches {
+		return false
+	}
+	return s.fileWide || (f.Pos.Line >= s.start && f.Pos.Line <= s.end)
+}
+
+// applySuppressions adds the findings not silenced by sups to report, followed by findings for
+// suppressions which have no reason or silence nothing.
+func applySuppressions(sups []*suppression, findings []Finding, report *Report) {
+	for _, f := range findings {
+		suppressed := false
+		for _, s := range sups {
+			if s.covers(f) {
+				s.matched = true
+				suppressed = true
+			}
+		}
+		if !suppressed {
+			report.AddFinding(f)
+		}
+	}
+
+	for _, s := range sups {
+		if len(s.ruleIDs) == 0 {
+			report.AddItem(s.pos, SuppressionRuleID,
+				fmt.Sprintf("%s directive must name the rules to ignore and give a reason", s.directive))
+			continue
+		}
+		ids := strings.Join(s.ruleIDs, ",")
+		if s.reason == "" {
+			report.AddItem(s.pos, SuppressionRuleID,
+				fmt.Sprintf("%s directive for %s must give a reason", s.directive, ids))
+		}
+		if !s.matched {
+			report.AddItem(s.pos, SuppressionRuleID,
+				fmt.Sprintf("%s directive for %s does not match any finding", s.directive, ids))
+		}
+	}
+}
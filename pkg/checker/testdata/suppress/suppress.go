@@ -0,0 +1,52 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suppress
+
+func bad() {}
+
+func reported() {
+	bad()
+}
+
+func trailing() {
+	bad() //lint:ignore no_bad trailing comment silences this line
+	bad()
+}
+
+func preceding() {
+	//lint:ignore no_bad comment above silences the next statement
+	bad()
+	bad()
+}
+
+//lint:ignore no_bad doc comment silences the whole declaration
+func declaration() {
+	bad()
+	bad()
+}
+
+func noReason() {
+	//lint:ignore no_bad
+	bad()
+}
+
+func stale() {
+	//lint:ignore no_bad nothing to silence here
+}
+
+func otherLinter() {
+	//lint:ignore SA4006 belongs to another linter
+	bad()
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typedFile is a file parsed and type checked as part of its package.
+type typedFile struct {
+	fset *token.FileSet
+	file *ast.File
+	info *types.Info
+	pkg  *types.Package
+}
+
+// needsTypes returns true if type information is to be loaded for a file checked by rules, which
+// is when one of them is a TypedRule, unless its types are optional and opts.LoadTypes is not set.
+func needsTypes(rules []Rule, opts Options) bool {
+	for _, rule := range rules {
+		if _, ok := rule.(TypedRule); !ok {
+			continue
+		}
+		if r, ok := rule.(OptionalTypedRule); !ok || !r.TypesOptional() || opts.LoadTypes {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTypedFiles loads the packages of the files which need types, and returns those files
+// parsed and type checked, by path. Files whose package cannot be loaded are missing from the
+// result, and get checked without type information. The errors of the packages are logged, so that
+// a failed load does not go unnoticed. Packages are loaded for the build configuration of opts.
+func loadTypedFiles(jobs []fileJob, opts Options) map[string]*typedFile {
+	wanted := map[string]bool{}
+	dirs := map[string]bool{}
+	for _, job := range jobs {
+		if needsTypes(job.rules, opts) {
+			wanted[job.path] = true
+			dirs[filepath.Dir(job.path)] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+	patterns := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		patterns = append(patterns, dir)
+	}
+	sort.Strings(patterns)
+
+	// The dependencies are imported from the export data built by the go command, only the
+	// packages of the files are type checked from source.
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports |
+			packages.NeedDeps | packages.NeedExportsFile,
+		Tests: true,
+		Env:   os.Environ(),
+	}
+	if opts.GOOS != "" {
+		cfg.Env = append(cfg.Env, "GOOS="+opts.GOOS)
+	}
+	if opts.GOARCH != "" {
+		cfg.Env = append(cfg.Env, "GOARCH="+opts.GOARCH)
+	}
+	if len(opts.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(opts.Tags, ",")}
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		log.Printf("unable to load packages, checking without type information: %v", err)
+		return nil
+	}
+
+	fset := token.NewFileSet()
+	sizes := types.SizesFor("gc", opts.buildContext().GOARCH)
+	files := map[string]*typedFile{}
+	for _, p := range pkgs {
+		roots := false
+		for _, name := range p.CompiledGoFiles {
+			roots = roots || (wanted[name] && files[name] == nil)
+		}
+		for _, e := range p.Errors {
+			log.Printf("error loading %s, its files may be checked without type information: %v", p.ID, e)
+		}
+		if !roots {
+			continue
+		}
+		checked, err := typeCheck(fset, sizes, p)
+		if err != nil {
+			log.Printf("unable to type check %s, checking without type information: %v", p.ID, err)
+			continue
+		}
+		for _, f := range checked.syntax {
+			if name := fset.Position(f.Pos()).Filename; wanted[name] && files[name] == nil {
+				files[name] = &typedFile{fset: fset, file: f, info: checked.info, pkg: checked.pkg}
+			}
+		}
+	}
+	return files
+}
+
+// checkedPackage is a package type checked by typeCheck.
+type checkedPackage struct {
+	pkg    *types.Package
+	info   *types.Info
+	syntax []*ast.File
+}
+
+// typeCheck parses the files of p and type checks them, importing the dependencies of p from
+// their export data. Type errors are logged, since the information recorded for the rest of the
+// package is still useful to rules.
+func typeCheck(fset *token.FileSet, sizes types.Sizes, p *packages.Package) (*checkedPackage, error) {
+	c := &checkedPackage{}
+	for _, name := range p.CompiledGoFiles {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		c.syntax = append(c.syntax, f)
+	}
+
+	lookup := func(path string) (io.ReadCloser, error) {
+		imp, ok := p.Imports[path]
+		if !ok {
+			return nil, fmt.Errorf("%s is not an import of %s", path, p.ID)
+		}
+		if imp.ExportFile == "" {
+			return nil, fmt.Errorf("no export data for %s", imp.ID)
+		}
+		return os.Open(imp.ExportFile)
+	}
+	var typeErrors []error
+	conf := types.Config{
+		Importer:    importer.ForCompiler(fset, "gc", lookup),
+		Sizes:       sizes,
+		FakeImportC: true,
+		Error: func(err error) {
+			typeErrors = append(typeErrors, err)
+		},
+	}
+	c.info = &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Scopes:     map[ast.Node]*types.Scope{},
+	}
+	c.pkg, _ = conf.Check(p.PkgPath, fset, c.syntax, c.info)
+	if len(typeErrors) > 0 {
+		log.Printf("type errors in %s, some findings may be missed: %v (%d errors)", p.ID, typeErrors[0], len(typeErrors))
+	}
+	return c, nil
+}
+
+// MatchCallFunc returns true if ce calls the package level function name of the package with
+// import path pkgPath. The callee is resolved through info, so renamed and dot imports match.
+func MatchCallFunc(info *types.Info, ce *ast.CallExpr, pkgPath string, name string) bool {
+	var id *ast.Ident
+	switch fun := ce.Fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return false
+	}
+	fn, ok := info.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath || fn.Name() != name {
+		return false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	return ok && sig.Recv() == nil
+}
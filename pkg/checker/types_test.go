@@ -0,0 +1,78 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTypedFiles(t *testing.T) {
+	file := getAbsPath("testdata/banned/banned.go")
+	rules := []Rule{requiredTypes{NewBannedAPIRule(BannedAPI{ID: "no_ioutil", Package: "io/ioutil"})}}
+	typed := loadTypedFiles([]fileJob{{path: file, rules: rules}}, Options{})
+	tf := typed[file]
+	if tf == nil || tf.info == nil || tf.pkg == nil {
+		t.Fatalf("expected %s to be type checked, received %v", file, typed)
+	}
+	if tf.pkg.Path() != "istio.io/tools/pkg/checker/testdata/banned" {
+		t.Errorf("unexpected package %s", tf.pkg.Path())
+	}
+}
+
+// requiredTypes hides the TypesOptional method of a rule, so that it always needs type
+// information.
+type requiredTypes struct {
+	TypedRule
+}
+
+func TestLoadTypedFilesOptional(t *testing.T) {
+	file := getAbsPath("testdata/banned/banned.go")
+	rules := newBannedTestRules()
+	if typed := loadTypedFiles([]fileJob{{path: file, rules: rules}}, Options{}); typed[file] != nil {
+		t.Errorf("expected %s not to be type checked for an optional typed rule", file)
+	}
+	if typed := loadTypedFiles([]fileJob{{path: file, rules: rules}}, Options{LoadTypes: true}); typed[file] == nil {
+		t.Errorf("expected %s to be type checked with LoadTypes", file)
+	}
+}
+
+func TestLoadTypedFilesLogsErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checker-types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "outside.go")
+	if err := ioutil.WriteFile(file, []byte("package outside\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	typed := loadTypedFiles([]fileJob{{path: file, rules: newBannedTestRules()}}, Options{LoadTypes: true})
+	if typed[file] != nil {
+		t.Errorf("expected %s outside of a module not to be type checked", file)
+	}
+	if !strings.Contains(logs.String(), "without type information") {
+		t.Errorf("expected the failed load to be logged, received %q", logs.String())
+	}
+}
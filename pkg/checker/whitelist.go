@@ -15,43 +15,131 @@
 package checker
 
 import (
+	"fmt"
+	"go/token"
 	"log"
-	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
+// ExpiredWhitelistRuleID is the rule ID of findings about expired whitelist entries.
+const ExpiredWhitelistRuleID = "whitelist_expired"
+
 // Whitelist determines if rules are whitelisted for the given paths.
 type Whitelist struct {
-	// Map from path to whitelisted rules.
-	ruleWhitelist map[string][]string
+	entries []WhitelistEntry
+	now     func() time.Time
+}
+
+// WhitelistEntry excludes rules from the files matching any of its paths.
+type WhitelistEntry struct {
+	// Paths are globs of the files the entry applies to, see MatchGlob.
+	Paths []string `json:"paths"`
+	// Rules are the IDs of the whitelisted rules, "*" whitelists all rules.
+	Rules []string `json:"rules"`
+	// Owner is who to ask about the entry.
+	Owner string `json:"owner,omitempty"`
+	// Expires is the date, as YYYY-MM-DD, from which the entry no longer applies.
+	Expires string `json:"expires,omitempty"`
+	// Reason explains why the rules are whitelisted.
+	Reason string `json:"reason,omitempty"`
+
+	source  token.Position // where the entry is defined, if it comes from a config file
+	expires time.Time
 }
 
 // NewWhitelist creates and returns a Whitelist object.
 func NewWhitelist(ruleWhitelist map[string][]string) *Whitelist {
-	return &Whitelist{ruleWhitelist: ruleWhitelist}
+	wl := &Whitelist{now: time.Now}
+	paths := make([]string, 0, len(ruleWhitelist))
+	for path := range ruleWhitelist {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		wl.entries = append(wl.entries, WhitelistEntry{Paths: []string{path}, Rules: ruleWhitelist[path]})
+	}
+	return wl
+}
+
+// AddEntries adds entries, such as the ones loaded from a config file, to the whitelist.
+func (wl *Whitelist) AddEntries(entries []WhitelistEntry) {
+	wl.entries = append(wl.entries, entries...)
 }
 
 // Apply returns true if the given rule is whitelisted for the given path.
 func (wl *Whitelist) Apply(path string, rule Rule) bool {
-	for _, skipRule := range wl.getWhitelistedRules(path) {
-		if skipRule == rule.GetID() {
+	return whitelisted(wl.getWhitelistedRules(path), rule)
+}
+
+// Filter returns the rules which are not whitelisted for the given path. Unlike calling Apply for
+// each rule, it matches the entries against path once.
+func (wl *Whitelist) Filter(path string, rules []Rule) []Rule {
+	skipRules := wl.getWhitelistedRules(path)
+	if len(skipRules) == 0 {
+		return rules
+	}
+	var active []Rule
+	for _, rule := range rules {
+		if !whitelisted(skipRules, rule) {
+			active = append(active, rule)
+		}
+	}
+	return active
+}
+
+// whitelisted returns true if rule is among skipRules, as returned by getWhitelistedRules.
+func whitelisted(skipRules []string, rule Rule) bool {
+	for _, skipRule := range skipRules {
+		if skipRule == rule.GetID() || skipRule == "*" {
 			return true
 		}
 	}
 	return false
 }
 
-// getWhitelistedRules returns the whitelisted rule given the path
+// ReportExpired adds a finding to report for each whitelist entry that has expired.
+func (wl *Whitelist) ReportExpired(report *Report) {
+	for _, entry := range wl.entries {
+		if !entry.expired(wl.now()) {
+			continue
+		}
+		msg := fmt.Sprintf("whitelist entry for rules %s on %s expired on %s",
+			strings.Join(entry.Rules, ","), strings.Join(entry.Paths, ","), entry.Expires)
+		if entry.Owner != "" {
+			msg += fmt.Sprintf(", owner %s", entry.Owner)
+		}
+		if entry.Reason != "" {
+			msg += fmt.Sprintf(", reason: %s", entry.Reason)
+		}
+		report.AddItem(entry.source, ExpiredWhitelistRuleID, msg)
+	}
+}
+
+// getWhitelistedRules returns the rules whitelisted by all the unexpired entries matching path.
 func (wl *Whitelist) getWhitelistedRules(path string) []string {
-	// Check whether path is whitelisted
-	for wp, whitelistedRules := range wl.ruleWhitelist {
-		// filepath.Match is needed for canonical matching
-		matched, err := filepath.Match(wp, path)
-		if err != nil {
-			log.Printf("file match returns error: %v", err)
+	var rules []string
+	now := wl.now()
+	for _, entry := range wl.entries {
+		if entry.expired(now) {
+			continue
 		}
-		if matched {
-			return whitelistedRules
+		for _, wp := range entry.Paths {
+			matched, err := MatchGlob(wp, path)
+			if err != nil {
+				log.Printf("file match returns error: %v", err)
+			}
+			if matched {
+				rules = append(rules, entry.Rules...)
+				break
+			}
 		}
 	}
-	return []string{}
+	return rules
+}
+
+// expired returns true if the entry has an expiry date which is not after now.
+func (e *WhitelistEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
 }
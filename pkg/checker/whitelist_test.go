@@ -0,0 +1,185 @@
+// Copyright 2020 Istio Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// namedRule is a rule that never reports anything.
+type namedRule string
+
+func (r namedRule) GetID() string {
+	return string(r)
+}
+
+func (r namedRule) Check(aNode ast.Node, fs *token.FileSet, lrp *Report) {}
+
+func TestMatchGlob(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		path    string
+		matched bool
+	}{
+		{"/istio/mixer/pkg/*", "/istio/mixer/pkg/a_test.go", true},
+		{"/istio/mixer/pkg/*", "/istio/mixer/pkg/sub/a_test.go", false},
+		{"/istio/**/*_test.go", "/istio/a_test.go", true},
+		{"/istio/**/*_test.go", "/istio/mixer/pkg/sub/a_test.go", true},
+		{"/istio/**/*_test.go", "/istio/mixer/pkg/a.go", false},
+		{"**/testdata/**", "/istio/pkg/testdata/a/b.go", true},
+		{"/istio/**", "/other/a.go", false},
+	} {
+		matched, err := MatchGlob(tc.pattern, tc.path)
+		if err != nil {
+			t.Errorf("MatchGlob(%q, %q) returned error: %v", tc.pattern, tc.path, err)
+		}
+		if matched != tc.matched {
+			t.Errorf("MatchGlob(%q, %q) = %v, expected %v", tc.pattern, tc.path, matched, tc.matched)
+		}
+	}
+}
+
+func TestWhitelistMergesEntries(t *testing.T) {
+	wl := NewWhitelist(map[string][]string{
+		"/istio/pkg/*":         {"no_sleep"},
+		"/istio/pkg/a_test.go": {"skip_issue"},
+		"/istio/other/*":       {"*"},
+	})
+	for _, tc := range []struct {
+		path        string
+		rule        string
+		whitelisted bool
+	}{
+		{"/istio/pkg/a_test.go", "no_sleep", true},
+		{"/istio/pkg/a_test.go", "skip_issue", true},
+		{"/istio/pkg/b_test.go", "skip_issue", false},
+		{"/istio/other/b_test.go", "skip_issue", true},
+	} {
+		if got := wl.Apply(tc.path, namedRule(tc.rule)); got != tc.whitelisted {
+			t.Errorf("Apply(%q, %q) = %v, expected %v", tc.path, tc.rule, got, tc.whitelisted)
+		}
+	}
+}
+
+func TestWhitelistFilter(t *testing.T) {
+	wl := NewWhitelist(map[string][]string{
+		"/istio/pkg/*":   {"no_sleep"},
+		"/istio/other/*": {"*"},
+	})
+	rules := []Rule{namedRule("no_sleep"), namedRule("skip_issue")}
+	for _, tc := range []struct {
+		path     string
+		expected []string
+	}{
+		{"/istio/pkg/a_test.go", []string{"skip_issue"}},
+		{"/istio/tests/a_test.go", []string{"no_sleep", "skip_issue"}},
+		{"/istio/other/a_test.go", []string{}},
+	} {
+		if ids := ruleIDs(wl.Filter(tc.path, rules)); !reflect.DeepEqual(ids, tc.expected) {
+			t.Errorf("Filter(%q) = %v, expected %v", tc.path, ids, tc.expected)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig("testdata/config/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wl := NewWhitelist(nil)
+	wl.AddEntries(config.Whitelist)
+	wl.now = func() time.Time { return time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC) }
+
+	if !wl.Apply(getAbsPath("testdata/config/pkg/a/b/c_test.go"), namedRule("no_sleep")) {
+		t.Error("expected no_sleep to be whitelisted for files matching pkg/**/*_test.go")
+	}
+	if wl.Apply(getAbsPath("testdata/config/pkg/foo/c_test.go"), namedRule("skip_issue")) {
+		t.Error("expected the expired entry to no longer apply")
+	}
+
+	report := NewLintReport()
+	wl.ReportExpired(report)
+	expectedRpts := []string{getAbsPath("testdata/config/config.yaml") + ":6:1:whitelist entry for rules skip_issue on " +
+		getAbsPath("testdata/config/pkg/foo/*") + " expired on 2020-01-31, reason: temporary (whitelist_expired)"}
+	if rpts := report.Items(); !reflect.DeepEqual(rpts, expectedRpts) {
+		t.Errorf("lint reports don't match\nReceived: %v\nExpected: %v", rpts, expectedRpts)
+	}
+}
+
+func TestConfigSeverities(t *testing.T) {
+	config, err := LoadConfig("testdata/config/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := NewLintReport()
+	report.AddItem(token.Position{Filename: getAbsPath("testdata/config/pkg/a/a_test.go"), Line: 1}, "no_sleep", "sleep")
+	report.AddItem(token.Position{Filename: getAbsPath("testdata/config/pkg/a/a_test.go"), Line: 2}, "skip_issue", "skip")
+	report.AddItem(token.Position{Filename: getAbsPath("testdata/config/pkg/legacy/b/b_test.go"), Line: 1}, "no_sleep", "sleep")
+	report.AddString("plain message")
+	config.ApplySeverities(report)
+
+	var got []Severity
+	for _, f := range report.Findings() {
+		got = append(got, f.Severity)
+	}
+	expected := []Severity{SeverityError, SeverityWarning, SeverityError, SeverityInfo}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("severities don't match\nReceived: %v\nExpected: %v", got, expected)
+	}
+}
+
+func TestConfigBannedAPIs(t *testing.T) {
+	config, err := LoadConfig("testdata/config/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := config.BannedAPIRules()
+	if len(rules) != 1 || rules[0].GetID() != "no_ioutil" {
+		t.Fatalf("unexpected banned API rules: %v", rules)
+	}
+	if s := rules[0].(SeverityRule).GetSeverity(); s != SeverityWarning {
+		t.Errorf("severity is %s, expected warning", s)
+	}
+	if allowed := config.BannedAPIs[0].Allowed; !reflect.DeepEqual(allowed, []string{getAbsPath("testdata/config/vendor/**")}) {
+		t.Errorf("allowed paths are not resolved against the config file: %v", allowed)
+	}
+}
+
+func TestConfigDecode(t *testing.T) {
+	config, err := LoadConfig("testdata/config/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings struct {
+		BannedAPIs []struct {
+			ID string `json:"id"`
+		} `json:"banned_apis"`
+	}
+	if err := config.Decode(&settings); err != nil {
+		t.Fatal(err)
+	}
+	if len(settings.BannedAPIs) != 1 || settings.BannedAPIs[0].ID != "no_ioutil" {
+		t.Errorf("unexpected banned_apis section: %v", settings.BannedAPIs)
+	}
+
+	settings.BannedAPIs = nil
+	if err := (&Config{}).Decode(&settings); err != nil || settings.BannedAPIs != nil {
+		t.Errorf("expected the settings to be unchanged without a config file, got %v, %v", settings.BannedAPIs, err)
+	}
+}